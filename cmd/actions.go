@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,14 +20,16 @@ var (
 	actionsPollInterval time.Duration
 	actionsWaitTimeout  time.Duration
 	actionsWaitAll      bool
+	actionsRerunAllJobs bool
 )
 
 var actionsCmd = &cobra.Command{
 	Use:   "actions",
 	Short: "List, watch, and show status of GitHub Actions for a tag",
 	Long: `List workflow runs triggered for a specific tag (e.g. after pushing a release tag),
-watch until all runs complete, or show a brief status summary. Requires GitHub config
-or GITHUB_TOKEN and a repo with remote origin.`,
+watch until all runs complete, or show a brief status summary. Requires GitHub credentials
+(see "Credentials" in the README) and a repo with a GitHub remote. On GitLab (see "forge" in config), the
+tag's pipelines are shown instead; this needs GITLAB_TOKEN or gitlab.token.`,
 }
 
 var actionsListCmd = &cobra.Command{
@@ -50,6 +53,14 @@ var actionsWatchCmd = &cobra.Command{
 	RunE:  runActionsWatch,
 }
 
+var actionsRerunCmd = &cobra.Command{
+	Use:   "rerun",
+	Short: "Re-run failed workflow runs for a tag",
+	Long: `Re-run the failed jobs (and the jobs that depend on them) of every failed workflow run for the tag, or
+every job with --whole-run. Only workflows that run on tag push are re-run unless --all is set.`,
+	RunE: runActionsRerun,
+}
+
 var actionsWorkflowsCmd = &cobra.Command{
 	Use:   "workflows",
 	Short: "List workflows that run when a release tag is pushed",
@@ -59,13 +70,16 @@ var actionsWorkflowsCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(actionsCmd)
-	actionsCmd.AddCommand(actionsListCmd, actionsStatusCmd, actionsWatchCmd, actionsWorkflowsCmd)
+	actionsCmd.AddCommand(actionsListCmd, actionsStatusCmd, actionsWatchCmd, actionsRerunCmd, actionsWorkflowsCmd)
 
 	actionsCmd.PersistentFlags().StringVar(&actionsTag, "tag", "", "git tag to list/watch (e.g. v1.0.0); required")
 
 	actionsWatchCmd.Flags().DurationVar(&actionsWaitTimeout, "timeout", 30*time.Minute, "maximum time to watch for runs to complete")
 	actionsWatchCmd.Flags().DurationVar(&actionsPollInterval, "poll-interval", 15*time.Second, "interval between status checks")
 	actionsWatchCmd.Flags().BoolVar(&actionsWaitAll, "all", false, "watch all workflow runs for the tag; if false, watch only workflows that run on tag push (from .github/workflows)")
+
+	actionsRerunCmd.Flags().BoolVar(&actionsRerunAllJobs, "whole-run", false, "re-run every job of the failed runs, not only the failed jobs")
+	actionsRerunCmd.Flags().BoolVar(&actionsWaitAll, "all", false, "re-run any failed workflow run for the tag; if false, only workflows that run on tag push (from .github/workflows)")
 }
 
 func actionsClientAndSHA(ctx context.Context) (*github.Client, string, error) {
@@ -87,30 +101,16 @@ func actionsClientAndSHA(ctx context.Context) (*github.Client, string, error) {
 	}
 	cfg.Resolve(repoAbs)
 
-	var owner, repo string
-	if cfg.GitHub != nil && cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
-		owner = cfg.GitHub.Owner
-		repo = cfg.GitHub.Repo
-	} else {
-		remote, err := git.RemoteOriginURL(ctx, repoAbs)
-		if err != nil {
-			return nil, "", fmt.Errorf("could not get remote: %w", err)
-		}
-		owner, repo, err = git.ParseGitHubOwnerRepo(remote)
-		if err != nil {
-			return nil, "", err
-		}
-	}
-
-	token := ""
-	if cfg.GitHub != nil && cfg.GitHub.Token != "" {
-		token = cfg.GitHub.Token
+	access, err := resolveGitHub(ctx, cfg, repoAbs, defaultRemote(cfg))
+	if err != nil {
+		return nil, "", err
 	}
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
+	if access.ts == nil {
+		return nil, "", fmt.Errorf("GitHub credentials required: %s", githubCredentialsHint)
 	}
-	if token == "" {
-		return nil, "", fmt.Errorf("GitHub token required: set GITHUB_TOKEN or github.token in config")
+	client, err := access.client(ctx, cfg)
+	if err != nil {
+		return nil, "", err
 	}
 
 	sha, err := git.RevParse(ctx, repoAbs, actionsTag)
@@ -118,10 +118,29 @@ func actionsClientAndSHA(ctx context.Context) (*github.Client, string, error) {
 		return nil, "", fmt.Errorf("resolve tag %q to SHA: %w", actionsTag, err)
 	}
 
-	client := github.NewClient(ctx, token, owner, repo)
 	return client, sha, nil
 }
 
+// actionsDryRunReport prints the GitHub repo and credentials the actions commands would use (--dry-run).
+func actionsDryRunReport() {
+	repoAbs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return
+	}
+	configPath := cfgFile
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(repoAbs, configPath)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return
+	}
+	cfg.Resolve(repoAbs)
+	for _, line := range githubDryRunReport(context.Background(), cfg, repoAbs, defaultRemote(cfg)) {
+		fmt.Fprintf(os.Stderr, "[dry-run] %s\n", line)
+	}
+}
+
 // workflowStatusSymbol returns an npm-style symbol for status/conclusion.
 func workflowStatusSymbol(status, conclusion string) string {
 	if status != "completed" {
@@ -137,8 +156,9 @@ func workflowStatusSymbol(status, conclusion string) string {
 	}
 }
 
-// printWorkflowTree prints runs in npm-style tree format to w (one line per run).
-func printWorkflowTree(w *os.File, runs []*github.WorkflowRun, tag string) {
+// printWorkflowTree prints runs in npm-style tree format to w (one line per run), with the running, queued
+// and failed jobs of each run from jobs (may be nil) underneath.
+func printWorkflowTree(w *os.File, runs []*github.WorkflowRun, jobs map[int64][]github.JobProgress, tag string) {
 	if len(runs) == 0 {
 		return
 	}
@@ -150,11 +170,14 @@ func printWorkflowTree(w *os.File, runs []*github.WorkflowRun, tag string) {
 			conclusion = status
 		}
 		sym := workflowStatusSymbol(status, conclusion)
-		prefix := "├── "
+		prefix, indent := "├── ", "│   "
 		if i == len(runs)-1 {
-			prefix = "└── "
+			prefix, indent = "└── ", "    "
 		}
 		fmt.Fprintf(w, "%s%s  %s #%d  %s\n", prefix, r.GetName(), sym, r.GetRunNumber(), r.GetHTMLURL())
+		for _, line := range activeJobLines([]*github.WorkflowRun{r}, jobs) {
+			fmt.Fprintf(w, "%s    %s\n", indent, line)
+		}
 	}
 	fmt.Fprintln(w)
 }
@@ -162,9 +185,15 @@ func printWorkflowTree(w *os.File, runs []*github.WorkflowRun, tag string) {
 func runActionsList(cmd *cobra.Command, args []string) error {
 	if dryRun {
 		fmt.Fprintf(os.Stderr, "[dry-run] Would list workflow runs for tag %s\n", actionsTag)
+		actionsDryRunReport()
 		return nil
 	}
 	ctx := context.Background()
+	if glClient, glSHA, ok, err := actionsGitLab(ctx); err != nil {
+		return err
+	} else if ok {
+		return runPipelinesList(ctx, glClient, glSHA)
+	}
 	client, sha, err := actionsClientAndSHA(ctx)
 	if err != nil {
 		return err
@@ -180,16 +209,22 @@ func runActionsList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	printWorkflowTree(os.Stdout, runs, actionsTag)
+	printWorkflowTree(os.Stdout, runs, nil, actionsTag)
 	return nil
 }
 
 func runActionsStatus(cmd *cobra.Command, args []string) error {
 	if dryRun {
 		fmt.Fprintf(os.Stderr, "[dry-run] Would show status of workflow runs for tag %s\n", actionsTag)
+		actionsDryRunReport()
 		return nil
 	}
 	ctx := context.Background()
+	if glClient, glSHA, ok, err := actionsGitLab(ctx); err != nil {
+		return err
+	} else if ok {
+		return runPipelinesStatus(ctx, glClient, glSHA)
+	}
 	client, sha, err := actionsClientAndSHA(ctx)
 	if err != nil {
 		return err
@@ -237,9 +272,15 @@ func runActionsStatus(cmd *cobra.Command, args []string) error {
 func runActionsWatch(cmd *cobra.Command, args []string) error {
 	if dryRun {
 		fmt.Fprintf(os.Stderr, "[dry-run] Would watch for workflow runs for tag %s (timeout %s)\n", actionsTag, actionsWaitTimeout)
+		actionsDryRunReport()
 		return nil
 	}
 	ctx := context.Background()
+	if glClient, glSHA, ok, err := actionsGitLab(ctx); err != nil {
+		return err
+	} else if ok {
+		return runPipelinesWatch(ctx, glClient, glSHA)
+	}
 	client, sha, err := actionsClientAndSHA(ctx)
 	if err != nil {
 		return err
@@ -250,54 +291,31 @@ func runActionsWatch(cmd *cobra.Command, args []string) error {
 	if !actionsWaitAll {
 		repoAbs, absErr := filepath.Abs(repoPath)
 		if absErr == nil {
-			tagPushTriggers, _ = github.WorkflowsTriggeredByTag(repoAbs, actionsTag)
+			triggers, _ := github.WorkflowsTriggeredByTag(repoAbs, actionsTag)
+			tagPushTriggers = github.TagPushWorkflows(triggers)
 		}
 	}
 
-	deadline := time.Now().Add(actionsWaitTimeout)
-	var runs []*github.WorkflowRun
-
-	for time.Now().Before(deadline) {
-		runs, err = client.ListWorkflowRunsForCommit(ctx, sha)
-		if err != nil {
-			return err
-		}
-
-		// Wait only on tag-push (publish) workflows when we have triggers from .github/workflows
-		waitedRuns := runs
-		if len(tagPushTriggers) > 0 {
-			waitedRuns = github.RunsForTagPushWorkflows(runs, tagPushTriggers)
-		}
-
-		if len(waitedRuns) == 0 {
-			if len(tagPushTriggers) > 0 {
-				fmt.Fprintf(os.Stderr, "No runs yet for tag-push workflows; waiting... (next check in %s)\n", actionsPollInterval)
-			} else {
-				fmt.Fprintf(os.Stderr, "No workflow runs found for tag %s (commit %s); waiting...\n", actionsTag, sha[:7])
+	waitedRuns, err := waitForWorkflows(ctx, client, workflowWait{
+		sha:      sha,
+		triggers: tagPushTriggers,
+		timeout:  actionsWaitTimeout,
+		interval: actionsPollInterval,
+		progress: func(runs []*github.WorkflowRun, jobs map[int64][]github.JobProgress) {
+			if len(runs) == 0 {
+				if len(tagPushTriggers) > 0 {
+					fmt.Fprintf(os.Stderr, "No runs yet for tag-push workflows; waiting... (next check in %s)\n", actionsPollInterval)
+				} else {
+					fmt.Fprintf(os.Stderr, "No workflow runs found for tag %s (commit %s); waiting...\n", actionsTag, sha[:7])
+				}
+				return
 			}
-			time.Sleep(actionsPollInterval)
-			continue
-		}
-
-		// Require a run for each expected workflow when in publish-only mode, then all must be finished
-		allSeen := true
-		if len(tagPushTriggers) > 0 && len(waitedRuns) < len(tagPushTriggers) {
-			allSeen = false
-		}
-		if allSeen && github.AllRunsFinished(waitedRuns) {
-			runs = waitedRuns
-			break
-		}
-
-		fmt.Fprintf(os.Stderr, "Waiting for workflows... (next check in %s)\n", actionsPollInterval)
-		printWorkflowTree(os.Stderr, waitedRuns, actionsTag)
-		time.Sleep(actionsPollInterval)
-	}
-
-	// Recompute waitedRuns for final messages (runs may have been set to waitedRuns on break)
-	waitedRuns := runs
-	if len(tagPushTriggers) > 0 {
-		waitedRuns = github.RunsForTagPushWorkflows(runs, tagPushTriggers)
+			fmt.Fprintf(os.Stderr, "Waiting for workflows... (next check in %s)\n", actionsPollInterval)
+			printWorkflowTree(os.Stderr, runs, jobs, actionsTag)
+		},
+	})
+	if err != nil && !errors.Is(err, errWorkflowTimeout) {
+		return err
 	}
 	if len(waitedRuns) == 0 {
 		if len(tagPushTriggers) > 0 {
@@ -308,19 +326,68 @@ func runActionsWatch(cmd *cobra.Command, args []string) error {
 		os.Exit(1)
 	}
 
-	if !github.AllRunsFinished(waitedRuns) {
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Timeout waiting for workflow runs to complete for tag %s\n", actionsTag)
 		os.Exit(1)
 	}
 
 	if github.AnyRunFailed(waitedRuns) {
 		fmt.Fprintf(os.Stderr, "One or more workflow runs failed for tag %s\n", actionsTag)
-		printWorkflowTree(os.Stderr, waitedRuns, actionsTag)
+		printWorkflowTree(os.Stderr, waitedRuns, nil, actionsTag)
+		if report := failedJobsReport(ctx, client, waitedRuns); report != "" {
+			fmt.Fprintln(os.Stderr, report)
+		}
 		os.Exit(1)
 	}
 
 	fmt.Fprintf(os.Stderr, "✓ All %d workflow run(s) completed successfully for tag %s\n", len(waitedRuns), actionsTag)
-	printWorkflowTree(os.Stderr, waitedRuns, actionsTag)
+	printWorkflowTree(os.Stderr, waitedRuns, nil, actionsTag)
+	return nil
+}
+
+func runActionsRerun(cmd *cobra.Command, args []string) error {
+	what := "failed jobs of failed"
+	if actionsRerunAllJobs {
+		what = "failed"
+	}
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "[dry-run] Would re-run %s workflow runs for tag %s\n", what, actionsTag)
+		actionsDryRunReport()
+		return nil
+	}
+	ctx := context.Background()
+	if glClient, glSHA, ok, err := actionsGitLab(ctx); err != nil {
+		return err
+	} else if ok {
+		return runPipelinesRerun(ctx, glClient, glSHA)
+	}
+	client, sha, err := actionsClientAndSHA(ctx)
+	if err != nil {
+		return err
+	}
+	runs, err := client.ListWorkflowRunsForCommit(ctx, sha)
+	if err != nil {
+		return err
+	}
+	if !actionsWaitAll {
+		if repoAbs, absErr := filepath.Abs(repoPath); absErr == nil {
+			if triggers, _ := github.WorkflowsTriggeredByTag(repoAbs, actionsTag); len(triggers) > 0 {
+				runs = github.RunsForTagPushWorkflows(runs, triggers)
+			}
+		}
+	}
+	failed := github.FailedRuns(runs)
+	if len(failed) == 0 {
+		fmt.Fprintf(os.Stderr, "No failed workflow runs for tag %s\n", actionsTag)
+		return nil
+	}
+	if _, err := rerunFailedRuns(ctx, client, failed, actionsRerunAllJobs); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "↻ Re-running %s workflow run(s) for tag %s:\n", what, actionsTag)
+	for _, r := range failed {
+		fmt.Fprintf(os.Stderr, "  %s #%d  %s\n", r.GetName(), r.GetRunNumber(), r.GetHTMLURL())
+	}
 	return nil
 }
 
@@ -350,27 +417,196 @@ func runActionsWorkflows(cmd *cobra.Command, args []string) error {
 		if actionsTag != "" {
 			fmt.Fprintf(os.Stdout, "No workflows in .github/workflows run on tag %s\n", actionsTag)
 		} else {
-			fmt.Fprintf(os.Stdout, "No workflows in .github/workflows are triggered by tag push\n")
+			fmt.Fprintf(os.Stdout, "No workflows in .github/workflows are triggered by tag push or release\n")
 		}
 		return nil
 	}
 	fmt.Fprintf(os.Stdout, "\nreleasebot@ %s\n", repoAbs)
 	if actionsTag != "" {
-		fmt.Fprintf(os.Stdout, "Workflows triggered by tag %s:\n", actionsTag)
+		fmt.Fprintf(os.Stdout, "Workflows triggered by tag %s (its push, GitHub Release and the workflow runs they trigger):\n", actionsTag)
 	} else {
-		fmt.Fprintf(os.Stdout, "Workflows that run on tag push:\n")
+		fmt.Fprintf(os.Stdout, "Workflows that run on tag push or release:\n")
 	}
 	for i, w := range triggers {
 		prefix := "├── "
 		if i == len(triggers)-1 {
 			prefix = "└── "
 		}
-		patterns := strings.Join(w.TagPatterns, ", ")
-		if patterns == "" {
-			patterns = "*"
-		}
-		fmt.Fprintf(os.Stdout, "%s%s  (%s)  tags: %s\n", prefix, w.Name, w.Path, patterns)
+		fmt.Fprintf(os.Stdout, "%s%s  (%s)  %s\n", prefix, w.Name, w.Path, workflowTriggerSummary(w))
 	}
 	fmt.Fprintln(os.Stdout)
 	return nil
 }
+
+// workflowTriggerSummary describes how a release tag triggers w: how it was triggered (when resolved for a tag
+// by WorkflowsTriggeredByTag), else its tag push filters and release trigger.
+func workflowTriggerSummary(w *github.WorkflowTrigger) string {
+	switch {
+	case w.After != "":
+		return fmt.Sprintf("after: %s (%s)", w.After, w.Event)
+	case w.Event == github.EventRelease:
+		return "on: release published"
+	}
+	var parts []string
+	if w.RunsOnTagPush {
+		switch {
+		case len(w.TagIgnorePatterns) > 0:
+			parts = append(parts, "tags-ignore: "+strings.Join(w.TagIgnorePatterns, ", "))
+		case len(w.TagPatterns) > 0:
+			parts = append(parts, "tags: "+strings.Join(w.TagPatterns, ", "))
+		default:
+			parts = append(parts, "tags: *")
+		}
+	}
+	if w.RunsOnRelease && w.Event == "" {
+		parts = append(parts, "on: release published")
+	}
+	return strings.Join(parts, "  ")
+}
+
+// errWorkflowTimeout is returned by waitForWorkflows when the runs did not all complete in time.
+var errWorkflowTimeout = errors.New("timeout waiting for workflows")
+
+// workflowWait configures waitForWorkflows.
+type workflowWait struct {
+	sha string
+	// triggers are the tag-push workflows to wait for; when empty, every run for the commit is waited on.
+	triggers []*github.WorkflowTrigger
+	timeout  time.Duration
+	interval time.Duration
+	// attempts is the run attempt each re-run run must reach (by run ID); until it does, the run is still
+	// reported with the previous attempt's conclusion and is treated as unfinished.
+	attempts map[int64]int
+	// progress is called after each poll that did not finish, with the runs waited on so far (empty until
+	// one starts) and the jobs of the unfinished and failed runs, by run ID.
+	progress func(runs []*github.WorkflowRun, jobs map[int64][]github.JobProgress)
+}
+
+// waitForWorkflows polls the workflow runs for w.sha until there is a run for every expected workflow and all
+// of them have completed. It returns the runs waited on; on timeout it returns them with errWorkflowTimeout.
+func waitForWorkflows(ctx context.Context, client *github.Client, w workflowWait) ([]*github.WorkflowRun, error) {
+	deadline := time.Now().Add(w.timeout)
+	var waitedRuns []*github.WorkflowRun
+	for time.Now().Before(deadline) {
+		runs, err := client.ListWorkflowRunsForCommit(ctx, w.sha)
+		if err != nil {
+			return nil, err
+		}
+		waitedRuns = runs
+		if len(w.triggers) > 0 {
+			waitedRuns = github.RunsForTagPushWorkflows(runs, w.triggers)
+		}
+		// Require a run for each expected workflow when waiting on tag-push workflows, then all must be finished.
+		allSeen := len(waitedRuns) > 0 && (len(w.triggers) == 0 || len(waitedRuns) >= len(w.triggers))
+		if allSeen && github.AllRunsFinished(waitedRuns) && rerunsStarted(waitedRuns, w.attempts) {
+			return waitedRuns, nil
+		}
+		if w.progress != nil {
+			w.progress(waitedRuns, workflowJobs(ctx, client, waitedRuns))
+		}
+		select {
+		case <-ctx.Done():
+			return waitedRuns, ctx.Err()
+		case <-time.After(w.interval):
+		}
+	}
+	return waitedRuns, errWorkflowTimeout
+}
+
+// rerunsStarted returns true when every run in attempts has reached its expected run attempt.
+func rerunsStarted(runs []*github.WorkflowRun, attempts map[int64]int) bool {
+	for _, r := range runs {
+		if r.GetRunAttempt() < attempts[r.GetID()] {
+			return false
+		}
+	}
+	return true
+}
+
+// rerunFailedRuns re-runs the failed runs (only their failed jobs unless wholeRun) and returns the run
+// attempt each will reach, by run ID, for workflowWait.attempts.
+func rerunFailedRuns(ctx context.Context, client *github.Client, runs []*github.WorkflowRun, wholeRun bool) (map[int64]int, error) {
+	attempts := make(map[int64]int)
+	for _, r := range github.FailedRuns(runs) {
+		rerun := client.RerunFailedJobs
+		if wholeRun {
+			rerun = client.RerunWorkflow
+		}
+		if err := rerun(ctx, r.GetID()); err != nil {
+			return attempts, err
+		}
+		attempts[r.GetID()] = r.GetRunAttempt() + 1
+	}
+	return attempts, nil
+}
+
+// workflowJobs returns the job progress of each unfinished or failed run, by run ID. Runs whose jobs cannot
+// be listed are left out; progress is best effort.
+func workflowJobs(ctx context.Context, client *github.Client, runs []*github.WorkflowRun) map[int64][]github.JobProgress {
+	jobs := make(map[int64][]github.JobProgress)
+	now := time.Now()
+	for _, r := range runs {
+		if r.GetStatus() == "completed" && r.GetConclusion() == "success" {
+			continue
+		}
+		list, err := client.ListWorkflowJobs(ctx, r.GetID())
+		if err != nil {
+			continue
+		}
+		for _, j := range list {
+			jobs[r.GetID()] = append(jobs[r.GetID()], github.JobProgressFrom(j, now))
+		}
+	}
+	return jobs
+}
+
+// activeJobLines returns one line per running, queued or failed job, for progress output.
+func activeJobLines(runs []*github.WorkflowRun, jobs map[int64][]github.JobProgress) []string {
+	var lines []string
+	for _, r := range runs {
+		for _, j := range jobs[r.GetID()] {
+			switch {
+			case j.IsFailed():
+				lines = append(lines, "✗ "+j.String())
+			case j.Status != "completed":
+				lines = append(lines, "⏳ "+j.String())
+			}
+		}
+	}
+	return lines
+}
+
+// workflowLogTailLines is how many lines of a failed job's log are shown.
+const workflowLogTailLines = 30
+
+// failedJobsReport describes each failed job of runs with its URL and the tail of its log, for display
+// after a workflow failure. Returns "" when no failed job is found.
+func failedJobsReport(ctx context.Context, client *github.Client, runs []*github.WorkflowRun) string {
+	var b strings.Builder
+	for _, r := range runs {
+		if r.GetStatus() != "completed" || r.GetConclusion() == "success" {
+			continue
+		}
+		list, err := client.ListWorkflowJobs(ctx, r.GetID())
+		if err != nil {
+			fmt.Fprintf(&b, "✗ %s #%d: %s (%v)\n", r.GetName(), r.GetRunNumber(), r.GetHTMLURL(), err)
+			continue
+		}
+		for _, j := range list {
+			p := github.JobProgressFrom(j, time.Now())
+			if !p.IsFailed() {
+				continue
+			}
+			fmt.Fprintf(&b, "✗ %s\n  %s\n", p, p.HTMLURL)
+			tail, err := client.JobLogTail(ctx, p.JobID, workflowLogTailLines)
+			if err != nil {
+				fmt.Fprintf(&b, "  (log unavailable: %v)\n", err)
+				continue
+			}
+			for _, line := range strings.Split(tail, "\n") {
+				fmt.Fprintf(&b, "  │ %s\n", line)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/crates"
+	"github.com/johnewart/releasebot/internal/goproxy"
+	"github.com/johnewart/releasebot/internal/maven"
+	"github.com/johnewart/releasebot/internal/npm"
+	"github.com/spf13/cobra"
+)
+
+// artifactKind describes a package registry with a check/watch command and a release step of the same name.
+type artifactKind struct {
+	// step is the release step type and command name (e.g. npm).
+	step string
+	// title names the registry in help and step names (e.g. crates.io).
+	title string
+	// arg is the package argument in usage (e.g. <groupId:artifactId>).
+	arg string
+	// example is a package for the help text.
+	example string
+	// newChecker returns a checker for the registry URL ("" = the public registry).
+	newChecker func(url string) artifact.Checker
+	// packages returns the configured packages and registry URL from release config.
+	packages func(rc *config.ReleaseConfig) ([]string, string)
+}
+
+// artifactKinds are the package registries beyond PyPI and container registries.
+var artifactKinds = []artifactKind{
+	{
+		step:    config.StepNpm,
+		title:   "npm",
+		arg:     "<package>",
+		example: "@scope/my-package 1.2.3",
+		newChecker: func(url string) artifact.Checker {
+			c := npm.NewClient(url)
+			c.Token = os.Getenv("NPM_TOKEN")
+			return c
+		},
+		packages: func(rc *config.ReleaseConfig) ([]string, string) { return rc.NpmPackage, rc.NpmRegistry },
+	},
+	{
+		step:       config.StepCrates,
+		title:      "crates.io",
+		arg:        "<crate>",
+		example:    "my-crate 1.2.3",
+		newChecker: func(url string) artifact.Checker { return crates.NewClient(url) },
+		packages:   func(rc *config.ReleaseConfig) ([]string, string) { return rc.Crate, "" },
+	},
+	{
+		step:    config.StepMaven,
+		title:   "Maven",
+		arg:     "<groupId:artifactId>",
+		example: "com.example:my-lib 1.2.3",
+		newChecker: func(url string) artifact.Checker {
+			c := maven.NewClient(url)
+			c.Username, c.Password = os.Getenv("MAVEN_USERNAME"), os.Getenv("MAVEN_PASSWORD")
+			return c
+		},
+		packages: func(rc *config.ReleaseConfig) ([]string, string) { return rc.MavenArtifact, rc.MavenRepository },
+	},
+	{
+		step:       config.StepGoProxy,
+		title:      "Go proxy",
+		arg:        "<module>",
+		example:    "github.com/org/repo v1.2.3",
+		newChecker: func(url string) artifact.Checker { return goproxy.NewClient(url) },
+		packages:   func(rc *config.ReleaseConfig) ([]string, string) { return rc.GoModule, rc.GoProxy },
+	},
+}
+
+// artifactKindFor returns the registry for a release step type.
+func artifactKindFor(step string) (artifactKind, bool) {
+	for _, k := range artifactKinds {
+		if k.step == step {
+			return k, true
+		}
+	}
+	return artifactKind{}, false
+}
+
+func init() {
+	for _, kind := range artifactKinds {
+		rootCmd.AddCommand(newArtifactCmd(kind))
+	}
+}
+
+// newArtifactCmd builds "<registry> check" and "<registry> watch" for kind.
+func newArtifactCmd(kind artifactKind) *cobra.Command {
+	var (
+		registryURL string
+		timeout     time.Duration
+		interval    time.Duration
+	)
+	parent := &cobra.Command{
+		Use:   kind.step,
+		Short: fmt.Sprintf("Check or watch for a package on %s", kind.title),
+		Long:  fmt.Sprintf(`Validate that a package (and optionally a version) exists on %s, or watch until it becomes available.`, kind.title),
+	}
+	parent.PersistentFlags().StringVar(&registryURL, "url", "", "registry URL (default: the public registry)")
+
+	ref := func(args []string) (name, version string) {
+		if len(args) == 2 {
+			version = args[1]
+		}
+		return args[0], version
+	}
+	check := &cobra.Command{
+		Use:   "check " + kind.arg + " [version]",
+		Short: fmt.Sprintf("Check if a package exists on %s", kind.title),
+		Long:  fmt.Sprintf(`Exits 0 if the package exists (and optionally the given version). Example: releasebot %s check %s`, kind.step, kind.example),
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, version := ref(args)
+			c := kind.newChecker(registryURL)
+			if dryRun {
+				fmt.Fprintf(os.Stderr, "[dry-run] Would check if %s exists on %s\n", artifact.Ref(name, version), c.Registry())
+				return nil
+			}
+			ok, err := c.Check(context.Background(), name, version)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Fprintf(os.Stderr, "%s not found on %s\n", artifact.Ref(name, version), c.Registry())
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "✓ %s is available on %s\n", artifact.Ref(name, version), c.Registry())
+			return nil
+		},
+	}
+	watch := &cobra.Command{
+		Use:   "watch " + kind.arg + " [version]",
+		Short: fmt.Sprintf("Watch until a package appears on %s", kind.title),
+		Long:  fmt.Sprintf(`Polls %s until the package (and optional version) exists or the timeout is reached. Useful after publishing from CI.`, kind.title),
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, version := ref(args)
+			c := kind.newChecker(registryURL)
+			if dryRun {
+				fmt.Fprintf(os.Stderr, "[dry-run] Would watch for %s on %s (timeout %s)\n", artifact.Ref(name, version), c.Registry(), timeout)
+				return nil
+			}
+			opts := artifact.WaitOptions{Timeout: timeout, Interval: interval, Progress: waitProgress(nil, logStderr)}
+			if err := artifact.Wait(context.Background(), c, name, version, opts); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "✓ %s is available on %s\n", artifact.Ref(name, version), c.Registry())
+			return nil
+		},
+	}
+	watch.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "maximum time to watch")
+	watch.Flags().DurationVar(&interval, "interval", 5*time.Second, "initial poll interval (later polls back off up to 30s)")
+	parent.AddCommand(check, watch)
+	return parent
+}
+
+// logStderr prints progress for watch commands.
+func logStderr(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// stepArtifactPackages returns the packages and registry URL for an npm, crates, maven or goproxy step:
+// the step's package and url, else the release config for the registry.
+func stepArtifactPackages(cfg *config.Config, sc config.ReleaseStepConfig, kind artifactKind) ([]string, string) {
+	var packages []string
+	var url string
+	if cfg.Release != nil {
+		packages, url = kind.packages(cfg.Release)
+	}
+	if sc.Package != "" {
+		packages = []string{sc.Package}
+	}
+	if sc.URL != "" {
+		url = sc.URL
+	}
+	return packages, url
+}
@@ -26,6 +26,7 @@ func init() {
 	changelogCmd.Flags().StringVar(&prevTag, "prev-tag", "", "previous release tag (overrides config)")
 	changelogCmd.Flags().StringVar(&headRef, "head", "HEAD", "head ref for changelog range (default: HEAD)")
 	changelogCmd.Flags().IntVar(&prLimit, "limit", 0, "max number of PRs to include (0 = no limit)")
+	changelogCmd.Flags().StringVar(&component, "component", "", "monorepo component (from components in config): its tags, paths and changelog output are used")
 }
 
 func runChangelog(cmd *cobra.Command, args []string) error {
@@ -44,17 +45,24 @@ func runChangelog(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	cfg.Resolve(repoAbs)
+	if err := cfg.UseComponent(component); err != nil {
+		return err
+	}
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return err
+	}
 
 	prev := prevTag
 	if prev == "" {
 		prev = cfg.PreviousReleaseTag
 	}
 	if prev == "" {
-		tags, err := git.ListTags(ctx, repoAbs)
+		tags, err := componentTags(ctx, repoAbs, cfg)
 		if err != nil {
 			return err
 		}
-		prev = semver.LatestStableTag(tags)
+		prev = semver.AddTagPrefix(cfg.TagPrefix(), semver.LatestStableTagScheme(tags, scheme))
 		if prev == "" {
 			return fmt.Errorf("could not determine previous release tag: use --prev-tag, set previous_release_tag in config, or ensure repo has semver tags (e.g. v1.0.0)")
 		}
@@ -95,6 +103,11 @@ func runChangelog(cmd *cobra.Command, args []string) error {
 		if len(src.PRs) > 0 {
 			sourceDesc = "PRs"
 		}
+		if usePRsRes {
+			for _, line := range githubDryRunReport(ctx, cfg, repoAbs, defaultRemote(cfg)) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "[dry-run] %s\n", line)
+			}
+		}
 		fmt.Fprintf(cmd.ErrOrStderr(), "[dry-run] Would generate changelog and write to %s (%d %s)\n", outPath, entries, sourceDesc)
 		return nil
 	}
@@ -130,6 +143,11 @@ func runChangelogTUI(ctx context.Context, cfg *config.Config, repoAbs, prev, hea
 			} else {
 				lines = append(lines, fmt.Sprintf("✅ Found %d commit(s) between %s and %s", len(src.Commits), prev, headRef))
 			}
+			if usePRsRes {
+				for _, line := range githubDryRunReport(ctx, cfg, repoAbs, defaultRemote(cfg)) {
+					lines = append(lines, "✅ "+line)
+				}
+			}
 			lines = append(lines, fmt.Sprintf("⏭️ Would generate changelog and write to %s (%d %s)", outPath, entries, sourceDesc))
 			ch <- taskPlanMsg{Lines: lines}
 		})
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/cache"
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/forge"
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/gitlab"
+)
+
+// forgeRepo is the project on the code host (GitHub or GitLab) that PRs, CI and releases go through.
+type forgeRepo struct {
+	// kind is forge.GitHub or forge.GitLab.
+	kind string
+	// owner and repo identify the project: the GitHub owner and repository, or the GitLab namespace and
+	// project name (also used as cache keys).
+	owner string
+	repo  string
+	// gitlabURL is the GitLab instance URL.
+	gitlabURL string
+	// webURL is the project's web URL (e.g. https://github.com/owner/repo or https://github.example.com/owner/repo).
+	webURL string
+}
+
+// project returns the GitLab project path (namespace/name).
+func (f forgeRepo) project() string {
+	return f.owner + "/" + f.repo
+}
+
+// forgeKind returns the configured forge, else gitlab when only gitlab is configured, else the forge detected
+// from remoteURL ("" when unknown, which means GitHub).
+func forgeKind(cfg *config.Config, remoteURL string) (string, error) {
+	kind, err := forge.Normalize(cfg.Forge)
+	if err != nil || kind != "" {
+		return kind, err
+	}
+	if cfg.GitLab != nil && cfg.GitHub == nil {
+		return forge.GitLab, nil
+	}
+	if remoteURL != "" {
+		return forge.Detect(remoteURL), nil
+	}
+	return forge.GitHub, nil
+}
+
+// resolveForgeRepo returns the forge project for the repo: github.owner/repo or gitlab.project and url from
+// config, else parsed from remote's URL (see resolveGitHubRepo).
+func resolveForgeRepo(ctx context.Context, cfg *config.Config, repoAbs, remote string) (forgeRepo, error) {
+	remoteURL, remoteErr := git.RemoteURL(ctx, repoAbs, remote)
+	kind, err := forgeKind(cfg, remoteURL)
+	if err != nil {
+		return forgeRepo{}, err
+	}
+	fr := forgeRepo{kind: kind}
+	if kind == forge.GitLab {
+		project, instance := "", ""
+		if cfg.GitLab != nil {
+			project, instance = strings.Trim(cfg.GitLab.Project, "/"), cfg.GitLab.URL
+		}
+		if project == "" || instance == "" {
+			if remoteErr != nil {
+				return fr, fmt.Errorf("gitlab not configured and could not get remote: %w", remoteErr)
+			}
+			host, path, err := git.ParseRemoteHostPath(remoteURL)
+			if err != nil {
+				return fr, fmt.Errorf("gitlab remote: %w", err)
+			}
+			if project == "" {
+				project = path
+			}
+			if instance == "" {
+				instance = "https://" + host
+			}
+		}
+		i := strings.LastIndex(project, "/")
+		if i < 0 {
+			return fr, fmt.Errorf("gitlab project %q: want namespace/project", project)
+		}
+		fr.owner, fr.repo = project[:i], project[i+1:]
+		fr.gitlabURL = strings.TrimSuffix(instance, "/")
+		fr.webURL = fr.gitlabURL + "/" + project
+		return fr, nil
+	}
+	fr.owner, fr.repo, _, err = resolveGitHubRepo(ctx, cfg, repoAbs, remote)
+	if err != nil {
+		return fr, err
+	}
+	fr.webURL = fmt.Sprintf("%s/%s/%s", githubWebURL(cfg), fr.owner, fr.repo)
+	return fr, nil
+}
+
+// gitlabToken returns gitlab.token, else GITLAB_TOKEN.
+func gitlabToken(cfg *config.Config) string {
+	if cfg.GitLab != nil && cfg.GitLab.Token != "" {
+		return cfg.GitLab.Token
+	}
+	return os.Getenv("GITLAB_TOKEN")
+}
+
+// newGitLabClient returns a GitLab client for the project (the token may be empty for public projects).
+func newGitLabClient(cfg *config.Config, fr forgeRepo) *gitlab.Client {
+	return gitlab.NewClient(fr.gitlabURL, gitlabToken(cfg), fr.project())
+}
+
+// forgeChangeSource returns the API for merged PRs (GitHub, using the commit PR cache) or merge requests (GitLab).
+func forgeChangeSource(ctx context.Context, cfg *config.Config, repoAbs string, fr forgeRepo) (forge.ChangeSource, error) {
+	if fr.kind == forge.GitLab {
+		return newGitLabClient(cfg, fr), nil
+	}
+	gh, err := newGitHubClient(ctx, cfg, fr.owner, fr.repo)
+	if err != nil {
+		return nil, err
+	}
+	gh.CommitCache = cache.NewCommitPRCache(filepath.Join(repoAbs, cache.DefaultDir, "commit_prs"))
+	return gh, nil
+}
+
+// releaseGitLabClient returns a GitLab client for the repo when its forge is GitLab (ok=false on GitHub). The
+// client is nil when no GitLab token is configured.
+func releaseGitLabClient(ctx context.Context, cfg *config.Config, repoAbs, remote string) (client *gitlab.Client, ok bool, err error) {
+	remoteURL, _ := git.RemoteURL(ctx, repoAbs, remote)
+	if kind, err := forgeKind(cfg, remoteURL); err != nil || kind != forge.GitLab {
+		return nil, false, err
+	}
+	fr, err := resolveForgeRepo(ctx, cfg, repoAbs, remote)
+	if err != nil {
+		return nil, true, err
+	}
+	if client = newGitLabClient(cfg, fr); !client.HasToken() {
+		return nil, true, nil
+	}
+	return client, true, nil
+}
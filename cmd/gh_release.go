@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ghReleaseTag    string
+	ghReleaseRemote string
+	ghReleaseAssets []string
+)
+
+var ghReleaseCmd = &cobra.Command{
+	Use:   "gh-release",
+	Short: "Create or update the GitHub Release for a tag",
+	Long: `Create the GitHub Release for a tag (default: the latest version tag), or update it if it
+already exists. The release notes are the tag's section of the changelog (changelog.output); when the
+changelog has no section, GitHub generates the notes. Tags with a prerelease kind (rc, alpha, ...) are
+marked as prereleases. Files matching --asset (or release.assets) globs are uploaded, replacing assets
+with the same name. Requires GitHub credentials (see the README). On GitLab (see "forge" in config) the
+GitLab Release is created instead and assets are uploaded as release links; this requires GITLAB_TOKEN
+or gitlab.token. Honors --dry-run.`,
+	RunE: runGHRelease,
+}
+
+func init() {
+	rootCmd.AddCommand(ghReleaseCmd)
+	ghReleaseCmd.Flags().StringVar(&ghReleaseTag, "tag", "", "release tag (default: latest version tag in repo)")
+	ghReleaseCmd.Flags().StringVar(&ghReleaseRemote, "remote", "", "remote used to find the GitHub repo (default: origin or release.remote in config)")
+	ghReleaseCmd.Flags().StringArrayVar(&ghReleaseAssets, "asset", nil, "file glob to upload (repeatable; overrides release.assets)")
+	ghReleaseCmd.Flags().StringVar(&component, "component", "", "monorepo component (from components in config): its tags and changelog output are used")
+}
+
+func runGHRelease(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	repoAbs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return fmt.Errorf("repo path: %w", err)
+	}
+
+	configPath := cfgFile
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(repoAbs, configPath)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	cfg.Resolve(repoAbs)
+	if err := cfg.UseComponent(component); err != nil {
+		return err
+	}
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return err
+	}
+
+	tag := ghReleaseTag
+	if tag == "" {
+		tags, err := componentTags(ctx, repoAbs, cfg)
+		if err != nil {
+			return err
+		}
+		tag = semver.AddTagPrefix(cfg.TagPrefix(), semver.LatestTagScheme(tags, scheme))
+		if tag == "" {
+			return fmt.Errorf("could not determine release tag: use --tag or ensure repo has semver tags (e.g. v1.0.0)")
+		}
+	}
+	if _, err := git.ValidateTag(ctx, repoAbs, tag); err != nil {
+		return err
+	}
+
+	remote := ghReleaseRemote
+	if remote == "" {
+		remote = defaultRemote(cfg)
+	}
+
+	outPath := "CHANGELOG.md"
+	if cfg.Changelog != nil && cfg.Changelog.Output != "" {
+		outPath = cfg.Changelog.Output
+	}
+	if !filepath.IsAbs(outPath) {
+		outPath = filepath.Join(repoAbs, outPath)
+	}
+
+	assets := ghReleaseAssets
+	if len(assets) == 0 && cfg.Release != nil {
+		assets = cfg.Release.Assets
+	}
+
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "[dry-run] Would create or update GitHub Release %s with notes from %s\n", tag, outPath)
+		for _, line := range githubDryRunReport(ctx, cfg, repoAbs, remote) {
+			fmt.Fprintf(os.Stderr, "[dry-run] %s\n", line)
+		}
+		files, err := expandAssetGlobs(repoAbs, assets)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			fmt.Fprintf(os.Stderr, "[dry-run] Would upload %s\n", f)
+		}
+		return nil
+	}
+
+	if gl, ok, err := releaseGitLabClient(ctx, cfg, repoAbs, remote); err != nil {
+		return err
+	} else if ok {
+		if gl == nil {
+			return fmt.Errorf("GitLab token required: set GITLAB_TOKEN or gitlab.token in config")
+		}
+		logf := func(format string, args ...interface{}) { fmt.Fprintf(os.Stderr, format, args...) }
+		rel, created, err := publishGitLabRelease(ctx, gl, repoAbs, tag, outPath, assets, logf)
+		if err != nil {
+			return err
+		}
+		action := "Updated"
+		if created {
+			action = "Created"
+		}
+		fmt.Fprintf(os.Stderr, "✓ %s GitLab Release %s: %s\n", action, tag, rel.Links.Self)
+		return nil
+	}
+
+	gh, err := releaseGitHubClient(ctx, cfg, repoAbs, remote)
+	if err != nil {
+		return err
+	}
+	if gh == nil {
+		return fmt.Errorf("GitHub credentials required: %s", githubCredentialsHint)
+	}
+	logf := func(format string, args ...interface{}) { fmt.Fprintf(os.Stderr, format, args...) }
+	rel, created, err := publishGitHubRelease(ctx, gh, cfg, repoAbs, tag, outPath, assets, logf)
+	if err != nil {
+		return err
+	}
+	action := "Updated"
+	if created {
+		action = "Created"
+	}
+	fmt.Fprintf(os.Stderr, "✓ %s GitHub Release %s: %s\n", action, tag, rel.GetHTMLURL())
+	return nil
+}
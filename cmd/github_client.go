@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/credentials"
+	"github.com/johnewart/releasebot/internal/forge"
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/github"
+	"golang.org/x/oauth2"
+)
+
+// githubCredentialsHint says how to provide GitHub credentials, for errors and warnings when none are found.
+const githubCredentialsHint = "set GITHUB_TOKEN, github.token or github.app in config, or log in with gh auth login"
+
+// githubAccess is the GitHub repository a command works on and the credentials it uses. Every command
+// resolves it the same way (resolveGitHub), so they agree on the repo and token.
+type githubAccess struct {
+	owner, repo string
+	// repoFrom says where owner/repo came from (e.g. "github.owner/repo in config", "remote origin").
+	repoFrom string
+	// ts authorizes requests; nil when no credentials were found.
+	ts oauth2.TokenSource
+	// credFrom says where the credentials came from (e.g. "GITHUB_TOKEN", "gh auth token").
+	credFrom string
+}
+
+// report returns lines describing the repo and credentials, for --dry-run output.
+func (a *githubAccess) report() []string {
+	creds := "GitHub credentials: none (unauthenticated requests are rate-limited)"
+	if a.ts != nil {
+		creds = "GitHub credentials from " + a.credFrom
+	}
+	return []string{fmt.Sprintf("GitHub repo %s/%s (from %s)", a.owner, a.repo, a.repoFrom), creds}
+}
+
+// client returns a client for the repo on github.com or github.base_url, authorized by the credentials.
+func (a *githubAccess) client(ctx context.Context, cfg *config.Config) (*github.Client, error) {
+	baseURL, uploadURL := "", ""
+	if cfg.GitHub != nil {
+		baseURL, uploadURL = cfg.GitHub.BaseURL, cfg.GitHub.UploadURL
+	}
+	return github.NewClientWithTokenSource(ctx, a.ts, baseURL, uploadURL, a.owner, a.repo)
+}
+
+// defaultRemote returns the remote releasebot reads the repo from and pushes to when none is given:
+// release.remote, else origin.
+func defaultRemote(cfg *config.Config) string {
+	if cfg.Release != nil && cfg.Release.Remote != "" {
+		return cfg.Release.Remote
+	}
+	return "origin"
+}
+
+// resolveGitHub resolves the GitHub repository (resolveGitHubRepo) and the credentials for it (githubCredentials).
+func resolveGitHub(ctx context.Context, cfg *config.Config, repoAbs, remote string) (*githubAccess, error) {
+	owner, repo, repoFrom, err := resolveGitHubRepo(ctx, cfg, repoAbs, remote)
+	if err != nil {
+		return nil, err
+	}
+	ts, credFrom, err := githubCredentials(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &githubAccess{owner: owner, repo: repo, repoFrom: repoFrom, ts: ts, credFrom: credFrom}, nil
+}
+
+// resolveGitHubRepo returns github.owner/repo from config, else the owner and repo parsed from remote's URL
+// on the GitHub host, with a description of where they came from.
+func resolveGitHubRepo(ctx context.Context, cfg *config.Config, repoAbs, remote string) (owner, repo, from string, err error) {
+	if cfg.GitHub != nil && cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
+		return cfg.GitHub.Owner, cfg.GitHub.Repo, "github.owner/repo in config", nil
+	}
+	remoteURL, err := git.RemoteURL(ctx, repoAbs, remote)
+	if err != nil {
+		return "", "", "", fmt.Errorf("github not configured and could not get remote %s: %w", remote, err)
+	}
+	owner, repo, err = git.ParseGitHubOwnerRepoOnHost(remoteURL, githubHost(cfg))
+	if err != nil {
+		return "", "", "", fmt.Errorf("github remote: %w", err)
+	}
+	return owner, repo, "remote " + remote, nil
+}
+
+// githubWebURL returns the web URL of the GitHub host: https://github.com, or the GitHub Enterprise Server
+// instance at github.base_url.
+func githubWebURL(cfg *config.Config) string {
+	if cfg.GitHub == nil {
+		return github.WebURL("")
+	}
+	return github.WebURL(cfg.GitHub.BaseURL)
+}
+
+// githubHost returns the host name of githubWebURL (e.g. github.com).
+func githubHost(cfg *config.Config) string {
+	return strings.TrimPrefix(strings.TrimPrefix(githubWebURL(cfg), "https://"), "http://")
+}
+
+// newGitHubClient returns a client for owner/repo on github.com or github.base_url, authorized by
+// githubCredentials. The client is unauthenticated (HasToken is false) when no credentials are found.
+func newGitHubClient(ctx context.Context, cfg *config.Config, owner, repo string) (*github.Client, error) {
+	ts, credFrom, err := githubCredentials(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	a := &githubAccess{owner: owner, repo: repo, ts: ts, credFrom: credFrom}
+	return a.client(ctx, cfg)
+}
+
+var (
+	githubCredMu     sync.Mutex
+	githubTokenFound = map[string]credentials.Token{}
+	githubAppSources = map[int64]oauth2.TokenSource{}
+)
+
+// githubCredentials returns the credentials for GitHub requests and where they came from, trying in order:
+// the GitHub App (github.app, else the GITHUB_APP_* variables), github.token, GITHUB_TOKEN or GH_TOKEN
+// (GH_ENTERPRISE_TOKEN on GitHub Enterprise Server), `gh auth token`, git credential helpers, and netrc.
+// Returns a nil token source when none has credentials.
+func githubCredentials(ctx context.Context, cfg *config.Config) (oauth2.TokenSource, string, error) {
+	app, err := githubAppAuth(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	// Lookups are kept for the process: gh and git run external commands, app tokens are minted over the
+	// network, and a release resolves credentials several times.
+	githubCredMu.Lock()
+	defer githubCredMu.Unlock()
+	if app != nil {
+		ts, ok := githubAppSources[app.InstallationID]
+		if !ok {
+			if ts, err = github.NewAppTokenSource(ctx, *app); err != nil {
+				return nil, "", err
+			}
+			githubAppSources[app.InstallationID] = ts
+		}
+		return ts, fmt.Sprintf("GitHub App %d (installation %d)", app.AppID, app.InstallationID), nil
+	}
+	if cfg.GitHub != nil && cfg.GitHub.Token != "" {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cfg.GitHub.Token}), "github.token in config", nil
+	}
+	host := githubHost(cfg)
+	envNames := []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	if host != "github.com" {
+		envNames = append(envNames, "GH_ENTERPRISE_TOKEN")
+	}
+	tok, ok := githubTokenFound[host]
+	if !ok {
+		var finder credentials.Finder
+		if tok, ok = finder.Find(ctx, host, envNames...); !ok {
+			return nil, "", nil
+		}
+		githubTokenFound[host] = tok
+	}
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: tok.Value}), tok.Source, nil
+}
+
+// githubAppAuth returns the GitHub App installation to authenticate as, from github.app with the
+// GITHUB_APP_ID, GITHUB_APP_INSTALLATION_ID and GITHUB_APP_PRIVATE_KEY_PATH variables filling in missing
+// fields. Returns nil when neither configures an app.
+func githubAppAuth(cfg *config.Config) (*github.AppAuth, error) {
+	var app config.GitHubAppConfig
+	baseURL := ""
+	if cfg.GitHub != nil {
+		baseURL = cfg.GitHub.BaseURL
+		if cfg.GitHub.App != nil {
+			app = *cfg.GitHub.App
+		}
+	}
+	for _, v := range []struct {
+		env string
+		id  *int64
+	}{{"GITHUB_APP_ID", &app.AppID}, {"GITHUB_APP_INSTALLATION_ID", &app.InstallationID}} {
+		if *v.id != 0 || os.Getenv(v.env) == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(os.Getenv(v.env), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", v.env, err)
+		}
+		*v.id = id
+	}
+	if app.PrivateKeyPath == "" {
+		app.PrivateKeyPath = os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH")
+	}
+	if app.AppID == 0 && app.InstallationID == 0 && app.PrivateKeyPath == "" {
+		return nil, nil
+	}
+	if app.AppID == 0 || app.InstallationID == 0 || app.PrivateKeyPath == "" {
+		return nil, fmt.Errorf("github.app needs app_id, installation_id and private_key_path")
+	}
+	data, err := os.ReadFile(app.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("github app private key: %w", err)
+	}
+	key, err := github.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("github app %s: %w", app.PrivateKeyPath, err)
+	}
+	return &github.AppAuth{AppID: app.AppID, InstallationID: app.InstallationID, PrivateKey: key, BaseURL: baseURL}, nil
+}
+
+// githubDryRunReport returns the lines describing the GitHub repo and credentials a command would use, for
+// --dry-run output: nothing on GitLab, and the resolution error as a line when it fails.
+func githubDryRunReport(ctx context.Context, cfg *config.Config, repoAbs, remote string) []string {
+	remoteURL, _ := git.RemoteURL(ctx, repoAbs, remote)
+	if kind, err := forgeKind(cfg, remoteURL); err != nil || kind != forge.GitHub {
+		return nil
+	}
+	access, err := resolveGitHub(ctx, cfg, repoAbs, remote)
+	if err != nil {
+		return []string{"GitHub: " + err.Error()}
+	}
+	return access.report()
+}
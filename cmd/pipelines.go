@@ -0,0 +1,422 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/johnewart/releasebot/internal/changelog"
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/gitlab"
+)
+
+// actionsGitLab returns the GitLab client and the tag's SHA when the repo's forge is GitLab (ok=false on
+// GitHub), so the actions commands show the tag's pipelines instead of workflow runs.
+func actionsGitLab(ctx context.Context) (client *gitlab.Client, sha string, ok bool, err error) {
+	repoAbs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("repo path: %w", err)
+	}
+	if actionsTag == "" {
+		return nil, "", false, fmt.Errorf("--tag is required")
+	}
+	configPath := cfgFile
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(repoAbs, configPath)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, "", false, err
+	}
+	cfg.Resolve(repoAbs)
+	client, ok, err = releaseGitLabClient(ctx, cfg, repoAbs, defaultRemote(cfg))
+	if err != nil || !ok {
+		return nil, "", false, err
+	}
+	if client == nil {
+		return nil, "", false, fmt.Errorf("GitLab token required: set GITLAB_TOKEN or gitlab.token in config")
+	}
+	sha, err = git.RevParse(ctx, repoAbs, actionsTag)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("resolve tag %q to SHA: %w", actionsTag, err)
+	}
+	return client, sha, true, nil
+}
+
+// pipelineStatusSymbol returns an npm-style symbol for a pipeline or job status.
+func pipelineStatusSymbol(status string) string {
+	switch status {
+	case "success":
+		return "✓"
+	case "failed", "canceled":
+		return "✗"
+	case "skipped", "manual":
+		return "○"
+	default:
+		return "⏳"
+	}
+}
+
+// printPipelineTree prints pipelines in npm-style tree format to w, with the running, pending and failed jobs
+// of each pipeline from jobs (may be nil) underneath.
+func printPipelineTree(w *os.File, pipelines []*gitlab.Pipeline, jobs map[int64][]*gitlab.Job, tag string) {
+	if len(pipelines) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nreleasebot@ tag %s\n", tag)
+	for i, p := range pipelines {
+		prefix, indent := "├── ", "│   "
+		if i == len(pipelines)-1 {
+			prefix, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%spipeline #%d (%s)  %s %s  %s\n", prefix, p.ID, p.Source, pipelineStatusSymbol(p.Status), p.Status, p.WebURL)
+		for _, line := range activePipelineJobLines([]*gitlab.Pipeline{p}, jobs) {
+			fmt.Fprintf(w, "%s    %s\n", indent, line)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func runPipelinesList(ctx context.Context, client *gitlab.Client, sha string) error {
+	pipelines, err := client.ListPipelinesForTag(ctx, actionsTag, sha)
+	if err != nil {
+		return err
+	}
+	if len(pipelines) == 0 {
+		fmt.Fprintf(os.Stderr, "No pipelines found for tag %s (commit %s)\n", actionsTag, sha[:7])
+		return nil
+	}
+	printPipelineTree(os.Stdout, pipelines, nil, actionsTag)
+	return nil
+}
+
+func runPipelinesStatus(ctx context.Context, client *gitlab.Client, sha string) error {
+	pipelines, err := client.ListPipelinesForTag(ctx, actionsTag, sha)
+	if err != nil {
+		return err
+	}
+	if len(pipelines) == 0 {
+		fmt.Fprintf(os.Stdout, "No pipelines for tag %s (commit %s)\n", actionsTag, sha[:7])
+		return nil
+	}
+	var success, failed, inProgress int
+	for _, p := range pipelines {
+		switch {
+		case p.IsFailed():
+			failed++
+		case p.IsFinished():
+			success++
+		default:
+			inProgress++
+		}
+	}
+	parts := []string{fmt.Sprintf("%d pipeline(s)", len(pipelines))}
+	if success > 0 {
+		parts = append(parts, fmt.Sprintf("✓ %d success", success))
+	}
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("✗ %d failed", failed))
+	}
+	if inProgress > 0 {
+		parts = append(parts, fmt.Sprintf("⏳ %d in progress", inProgress))
+	}
+	fmt.Fprintln(os.Stdout, strings.Join(parts, "  ")+".")
+	return nil
+}
+
+func runPipelinesWatch(ctx context.Context, client *gitlab.Client, sha string) error {
+	pipelines, err := waitForPipelines(ctx, client, pipelineWait{
+		tag:      actionsTag,
+		sha:      sha,
+		timeout:  actionsWaitTimeout,
+		interval: actionsPollInterval,
+		progress: func(pipelines []*gitlab.Pipeline, jobs map[int64][]*gitlab.Job) {
+			if len(pipelines) == 0 {
+				fmt.Fprintf(os.Stderr, "No pipelines found for tag %s (commit %s); waiting...\n", actionsTag, sha[:7])
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Waiting for pipelines... (next check in %s)\n", actionsPollInterval)
+			printPipelineTree(os.Stderr, pipelines, jobs, actionsTag)
+		},
+	})
+	if err != nil && err != errWorkflowTimeout {
+		return err
+	}
+	if len(pipelines) == 0 {
+		fmt.Fprintf(os.Stderr, "No pipelines found for tag %s before timeout\n", actionsTag)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Timeout waiting for pipelines to complete for tag %s\n", actionsTag)
+		os.Exit(1)
+	}
+	if anyPipelineFailed(pipelines) {
+		fmt.Fprintf(os.Stderr, "One or more pipelines failed for tag %s\n", actionsTag)
+		printPipelineTree(os.Stderr, pipelines, nil, actionsTag)
+		if report := failedPipelinesReport(ctx, client, pipelines); report != "" {
+			fmt.Fprintln(os.Stderr, report)
+		}
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "✓ All %d pipeline(s) completed successfully for tag %s\n", len(pipelines), actionsTag)
+	printPipelineTree(os.Stderr, pipelines, nil, actionsTag)
+	return nil
+}
+
+func runPipelinesRerun(ctx context.Context, client *gitlab.Client, sha string) error {
+	pipelines, err := client.ListPipelinesForTag(ctx, actionsTag, sha)
+	if err != nil {
+		return err
+	}
+	var failed []*gitlab.Pipeline
+	for _, p := range pipelines {
+		if p.IsFailed() {
+			failed = append(failed, p)
+		}
+	}
+	if len(failed) == 0 {
+		fmt.Fprintf(os.Stderr, "No failed pipelines for tag %s\n", actionsTag)
+		return nil
+	}
+	if _, err := retryFailedPipelines(ctx, client, failed); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "↻ Retrying failed jobs of %d pipeline(s) for tag %s:\n", len(failed), actionsTag)
+	for _, p := range failed {
+		fmt.Fprintf(os.Stderr, "  pipeline #%d  %s\n", p.ID, p.WebURL)
+	}
+	return nil
+}
+
+// pipelineWait configures waitForPipelines.
+type pipelineWait struct {
+	tag      string
+	sha      string
+	timeout  time.Duration
+	interval time.Duration
+	// retried holds the IDs of pipelines whose failed jobs were retried. GitLab updates their status
+	// asynchronously, so until it leaves failed/canceled a retried pipeline is treated as unfinished.
+	retried map[int64]bool
+	// progress is called after each poll that did not finish, with the tag's pipelines (empty until one starts)
+	// and the jobs of the unfinished and failed pipelines, by pipeline ID.
+	progress func(pipelines []*gitlab.Pipeline, jobs map[int64][]*gitlab.Job)
+}
+
+// waitForPipelines polls the pipelines for w.tag at w.sha until there is at least one and all have finished.
+// It returns the pipelines; on timeout it returns them with errWorkflowTimeout.
+func waitForPipelines(ctx context.Context, client *gitlab.Client, w pipelineWait) ([]*gitlab.Pipeline, error) {
+	deadline := time.Now().Add(w.timeout)
+	var pipelines []*gitlab.Pipeline
+	for time.Now().Before(deadline) {
+		var err error
+		pipelines, err = client.ListPipelinesForTag(ctx, w.tag, w.sha)
+		if err != nil {
+			return nil, err
+		}
+		finished := len(pipelines) > 0
+		for _, p := range pipelines {
+			if w.retried[p.ID] && p.IsFailed() {
+				// Still the status from before the retry.
+				finished = false
+				continue
+			}
+			// The retry has started; a later failure is a real one.
+			delete(w.retried, p.ID)
+			finished = finished && p.IsFinished()
+		}
+		if finished {
+			return pipelines, nil
+		}
+		if w.progress != nil {
+			w.progress(pipelines, pipelineJobs(ctx, client, pipelines))
+		}
+		select {
+		case <-ctx.Done():
+			return pipelines, ctx.Err()
+		case <-time.After(w.interval):
+		}
+	}
+	return pipelines, errWorkflowTimeout
+}
+
+// anyPipelineFailed returns true if any pipeline failed or was canceled.
+func anyPipelineFailed(pipelines []*gitlab.Pipeline) bool {
+	for _, p := range pipelines {
+		if p.IsFailed() {
+			return true
+		}
+	}
+	return false
+}
+
+// retryFailedPipelines retries the failed jobs of each failed pipeline and returns the IDs of the retried
+// pipelines, for pipelineWait.retried.
+func retryFailedPipelines(ctx context.Context, client *gitlab.Client, pipelines []*gitlab.Pipeline) (map[int64]bool, error) {
+	retried := make(map[int64]bool)
+	for _, p := range pipelines {
+		if !p.IsFailed() {
+			continue
+		}
+		if err := client.RetryPipeline(ctx, p.ID); err != nil {
+			return retried, err
+		}
+		retried[p.ID] = true
+	}
+	return retried, nil
+}
+
+// pipelineJobs returns the jobs of each unfinished or failed pipeline, by pipeline ID. Pipelines whose jobs
+// cannot be listed are left out; progress is best effort.
+func pipelineJobs(ctx context.Context, client *gitlab.Client, pipelines []*gitlab.Pipeline) map[int64][]*gitlab.Job {
+	jobs := make(map[int64][]*gitlab.Job)
+	for _, p := range pipelines {
+		if p.IsFinished() && !p.IsFailed() {
+			continue
+		}
+		if list, err := client.ListPipelineJobs(ctx, p.ID); err == nil {
+			jobs[p.ID] = list
+		}
+	}
+	return jobs
+}
+
+// activePipelineJobLines returns one line per running, pending or failed job, for progress output.
+func activePipelineJobLines(pipelines []*gitlab.Pipeline, jobs map[int64][]*gitlab.Job) []string {
+	var lines []string
+	for _, p := range pipelines {
+		for _, j := range jobs[p.ID] {
+			switch {
+			case j.IsFailed():
+				lines = append(lines, "✗ "+j.String())
+			case j.Status == "running" || j.Status == "pending":
+				lines = append(lines, "⏳ "+j.String())
+			}
+		}
+	}
+	return lines
+}
+
+// failedPipelinesReport describes each failed job of the failed pipelines with its URL and the tail of its
+// log, for display after a pipeline failure. Returns "" when no failed job is found.
+func failedPipelinesReport(ctx context.Context, client *gitlab.Client, pipelines []*gitlab.Pipeline) string {
+	var b strings.Builder
+	for _, p := range pipelines {
+		if !p.IsFailed() {
+			continue
+		}
+		list, err := client.ListPipelineJobs(ctx, p.ID)
+		if err != nil {
+			fmt.Fprintf(&b, "✗ pipeline #%d: %s (%v)\n", p.ID, p.WebURL, err)
+			continue
+		}
+		for _, j := range list {
+			if !j.IsFailed() {
+				continue
+			}
+			fmt.Fprintf(&b, "✗ %s\n  %s\n", j, j.WebURL)
+			tail, err := client.JobLogTail(ctx, j.ID, workflowLogTailLines)
+			if err != nil {
+				fmt.Fprintf(&b, "  (log unavailable: %v)\n", err)
+				continue
+			}
+			for _, line := range strings.Split(tail, "\n") {
+				fmt.Fprintf(&b, "  │ %s\n", line)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// releaseStepWaitPipelines is the wait_workflows step on GitLab: it waits for the tag's pipelines at sha to
+// finish, retrying the failed jobs of failed pipelines up to the step's reruns.
+func releaseStepWaitPipelines(params *releaseParams, sc config.ReleaseStepConfig, client *gitlab.Client, sha string, logf func(format string, args ...interface{})) (bool, error) {
+	ctx := params.ctx
+	pollInterval := 15 * time.Second
+	reruns := stepWorkflowReruns(params.cfg, sc)
+	var last string
+	wait := pipelineWait{
+		tag:      params.nextTagForRef,
+		sha:      sha,
+		timeout:  stepTimeout(sc, params.releaseWaitTo),
+		interval: pollInterval,
+		progress: func(pipelines []*gitlab.Pipeline, jobs map[int64][]*gitlab.Job) {
+			lines := activePipelineJobLines(pipelines, jobs)
+			if len(pipelines) == 0 {
+				lines = []string{"Waiting for release pipelines to start..."}
+			} else if len(lines) == 0 {
+				lines = []string{"Waiting for pipelines..."}
+			}
+			text := strings.Join(lines, "\n")
+			if params.stepDetail != nil {
+				params.stepDetail(text)
+				return
+			}
+			if text == last {
+				return
+			}
+			last = text
+			logf("%s (next check in %s)\n", text, pollInterval)
+		},
+	}
+	// Each attempt (the first wait and each retry) gets the full timeout.
+	for attempt := 0; ; attempt++ {
+		pipelines, err := waitForPipelines(ctx, client, wait)
+		if errors.Is(err, errWorkflowTimeout) {
+			return false, fmt.Errorf("timeout waiting for release pipelines")
+		}
+		if err != nil {
+			return false, fmt.Errorf("list pipelines: %w", err)
+		}
+		if !anyPipelineFailed(pipelines) {
+			logf("✓ All release pipeline(s) completed\n")
+			return false, nil
+		}
+		report := failedPipelinesReport(ctx, client, pipelines)
+		if attempt >= reruns {
+			if report != "" {
+				return false, fmt.Errorf("one or more release pipelines failed\n%s", report)
+			}
+			return false, fmt.Errorf("one or more release pipelines failed")
+		}
+		if report != "" {
+			logf("%s\n", report)
+		}
+		logf("Retrying failed jobs of release pipelines (retry %d of %d)\n", attempt+1, reruns)
+		if params.stepDetail != nil {
+			params.stepDetail(fmt.Sprintf("Retrying failed pipeline jobs (retry %d of %d)", attempt+1, reruns))
+		}
+		if wait.retried, err = retryFailedPipelines(ctx, client, pipelines); err != nil {
+			return false, err
+		}
+	}
+}
+
+// publishGitLabRelease creates or updates the GitLab Release for tag with tag's section of the changelog at
+// changelogPath as its description, then uploads and links the files matching the asset globs (relative to
+// repoAbs). rel and created are set once the release exists, even if an upload fails.
+func publishGitLabRelease(ctx context.Context, client *gitlab.Client, repoAbs, tag, changelogPath string, assetGlobs []string, logf func(format string, args ...interface{})) (rel *gitlab.Release, created bool, err error) {
+	files, err := expandAssetGlobs(repoAbs, assetGlobs)
+	if err != nil {
+		return nil, false, err
+	}
+	notes := ""
+	if data, err := os.ReadFile(changelogPath); err == nil {
+		notes = changelog.Section(string(data), tag)
+	}
+	rel, created, err = client.CreateOrUpdateRelease(ctx, gitlab.ReleaseOptions{Tag: tag, Body: notes})
+	if err != nil {
+		return nil, false, err
+	}
+	for _, f := range files {
+		if err := client.UploadReleaseAsset(ctx, tag, f); err != nil {
+			return rel, created, err
+		}
+		logf("✓ Uploaded %s\n", filepath.Base(f))
+	}
+	return rel, created, nil
+}
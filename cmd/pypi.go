@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/johnewart/releasebot/internal/config"
 	"github.com/johnewart/releasebot/internal/pypi"
 	"github.com/spf13/cobra"
 )
@@ -13,12 +16,15 @@ import (
 var (
 	pypiWaitTimeout  time.Duration
 	pypiWaitInterval time.Duration
+	pypiIndex        string
+	pypiFiles        []string
 )
 
 var pypiCmd = &cobra.Command{
 	Use:   "pypi",
 	Short: "Check or watch for a Python package on PyPI",
-	Long:  `Validate that a package (e.g. my-package or my-package==1.0.0) exists on PyPI, or watch until it becomes available.`,
+	Long: `Validate that a package (e.g. my-package or my-package==1.0.0) exists on PyPI or another Python index, or watch until it becomes available.
+Use --index with a name from pypi.indexes in config, pypi, testpypi, or an index URL.`,
 }
 
 var pypiCheckCmd = &cobra.Command{
@@ -42,17 +48,71 @@ func init() {
 	pypiCmd.AddCommand(pypiCheckCmd)
 	pypiCmd.AddCommand(pypiWatchCmd)
 
+	pypiCmd.PersistentFlags().StringVar(&pypiIndex, "index", "", "index to check: name from pypi.indexes in config, pypi, testpypi, or a URL (default pypi)")
+	pypiCmd.PersistentFlags().StringArrayVar(&pypiFiles, "file", nil, "required distribution when a version is given: sdist, wheel, or wheel tags like cp311-manylinux_x86_64 (repeatable)")
 	pypiWatchCmd.Flags().DurationVar(&pypiWaitTimeout, "timeout", 5*time.Minute, "maximum time to watch")
-	pypiWatchCmd.Flags().DurationVar(&pypiWaitInterval, "interval", 5*time.Second, "poll interval")
+	pypiWatchCmd.Flags().DurationVar(&pypiWaitInterval, "interval", 5*time.Second, "initial poll interval (later polls back off up to 30s)")
+}
+
+// resolvePyPIIndex returns the index for name: an entry of pypi.indexes, the built-in "pypi" (also for "")
+// or "testpypi", or an index URL. Credentials in config are expanded with environment variables.
+func resolvePyPIIndex(cfg *config.Config, name string) (pypi.Index, error) {
+	if cfg != nil && cfg.PyPI != nil {
+		if ic, ok := cfg.PyPI.Indexes[name]; ok && ic != nil {
+			if ic.URL == "" {
+				return pypi.Index{}, fmt.Errorf("pypi index %q: url is required", name)
+			}
+			return pypi.Index{
+				URL:      ic.URL,
+				Username: os.ExpandEnv(ic.Username),
+				Password: os.ExpandEnv(ic.Password),
+				Token:    os.ExpandEnv(ic.Token),
+			}, nil
+		}
+	}
+	switch name {
+	case "", "pypi":
+		return pypi.Index{URL: pypi.DefaultIndexURL}, nil
+	case "testpypi":
+		return pypi.Index{URL: pypi.TestPyPIIndexURL}, nil
+	}
+	if strings.HasPrefix(name, "https://") || strings.HasPrefix(name, "http://") {
+		return pypi.Index{URL: name}, nil
+	}
+	return pypi.Index{}, fmt.Errorf("unknown pypi index %q (not in pypi.indexes config)", name)
+}
+
+// pypiCommandIndex resolves --index, loading config only when it may name a configured index.
+func pypiCommandIndex() (pypi.Index, error) {
+	if pypiIndex == "" || strings.Contains(pypiIndex, "://") {
+		return resolvePyPIIndex(nil, pypiIndex)
+	}
+	repoAbs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return pypi.Index{}, fmt.Errorf("repo path: %w", err)
+	}
+	configPath := cfgFile
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(repoAbs, configPath)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return pypi.Index{}, err
+	}
+	return resolvePyPIIndex(cfg, pypiIndex)
 }
 
 func runPypiCheck(cmd *cobra.Command, args []string) error {
+	index, err := pypiCommandIndex()
+	if err != nil {
+		return err
+	}
 	if dryRun {
 		ref := args[0]
 		if len(args) == 2 {
 			ref = args[0] + "==" + args[1]
 		}
-		fmt.Fprintf(os.Stderr, "[dry-run] Would check if package %s exists on PyPI\n", ref)
+		fmt.Fprintf(os.Stderr, "[dry-run] Would check if package %s exists on %s\n", ref, index.Host())
 		return nil
 	}
 	ctx := context.Background()
@@ -61,7 +121,8 @@ func runPypiCheck(cmd *cobra.Command, args []string) error {
 	if len(args) == 2 {
 		version = args[1]
 	}
-	ok, err := pypi.Check(ctx, name, version)
+	client := pypi.NewClient(index)
+	ok, err := client.Check(ctx, name, version)
 	if err != nil {
 		return err
 	}
@@ -70,24 +131,33 @@ func runPypiCheck(cmd *cobra.Command, args []string) error {
 		if version != "" {
 			ref = name + "==" + version
 		}
-		fmt.Fprintf(os.Stderr, "package %s not found on PyPI\n", ref)
+		fmt.Fprintf(os.Stderr, "package %s not found on %s\n", ref, index.Host())
 		os.Exit(1)
 	}
+	if version != "" {
+		if err := client.Verify(ctx, name, version, pypi.VerifyOptions{Files: pypiFiles}); err != nil {
+			return err
+		}
+	}
 	ref := name
 	if version != "" {
 		ref = name + "==" + version
 	}
-	fmt.Fprintf(os.Stderr, "✓ Package %s is available on PyPI\n", ref)
+	fmt.Fprintf(os.Stderr, "✓ Package %s is available on %s\n", ref, index.Host())
 	return nil
 }
 
 func runPypiWatch(cmd *cobra.Command, args []string) error {
+	index, err := pypiCommandIndex()
+	if err != nil {
+		return err
+	}
 	if dryRun {
 		ref := args[0]
 		if len(args) == 2 {
 			ref = args[0] + "==" + args[1]
 		}
-		fmt.Fprintf(os.Stderr, "[dry-run] Would watch for package %s on PyPI (timeout %s)\n", ref, pypiWaitTimeout)
+		fmt.Fprintf(os.Stderr, "[dry-run] Would watch for package %s on %s (timeout %s)\n", ref, index.Host(), pypiWaitTimeout)
 		return nil
 	}
 	ctx := context.Background()
@@ -99,14 +169,16 @@ func runPypiWatch(cmd *cobra.Command, args []string) error {
 	opts := pypi.WaitOptions{
 		Timeout:  pypiWaitTimeout,
 		Interval: pypiWaitInterval,
+		Verify:   pypi.VerifyOptions{Files: pypiFiles},
+		Progress: waitProgress(nil, logStderr),
 	}
-	if err := pypi.Wait(ctx, name, version, opts); err != nil {
+	if err := pypi.NewClient(index).Wait(ctx, name, version, opts); err != nil {
 		return err
 	}
 	ref := name
 	if version != "" {
 		ref = name + "==" + version
 	}
-	fmt.Fprintf(os.Stderr, "✓ Package %s is available on PyPI\n", ref)
+	fmt.Fprintf(os.Stderr, "✓ Package %s is available on %s\n", ref, index.Host())
 	return nil
 }
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/johnewart/releasebot/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	registryWaitTimeout  time.Duration
+	registryWaitInterval time.Duration
+	registryPlatforms    []string
+	registryLabels       []string
+)
+
+var registryCmd = &cobra.Command{
+	Use:     "registry",
+	Aliases: []string{"dockerhub"},
+	Short:   "Check or watch for a container image in its registry",
+	Long: `Validate that an image exists in its registry, or watch until it becomes available. Images without a
+registry host are on Docker Hub (e.g. nginx:latest, myorg/myimage:v1.0); others name the registry
+(e.g. ghcr.io/org/app:v1.2.3, registry.example.com:5000/team/app:1.0). Credentials come from
+~/.docker/config.json (auths or credential helpers), as written by docker login.
+
+With --platform, the image's index (manifest list) must include each platform (e.g. linux/amd64,
+linux/arm64). With --label key=value, each of those platforms' image config must have the label
+(e.g. --label org.opencontainers.image.version=1.2.3; a leading "v" is ignored when comparing).`,
+}
+
+var registryCheckCmd = &cobra.Command{
+	Use:   "check <image>",
+	Short: "Check if an image exists in its registry",
+	Long:  `Exits 0 if the image exists, 1 if not. Image can be e.g. nginx:latest, myorg/myimage:v1.0 or ghcr.io/org/app:v1.0.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRegistryCheck,
+}
+
+var registryWatchCmd = &cobra.Command{
+	Use:   "watch <image>",
+	Short: "Watch until an image appears in its registry",
+	Long:  `Polls the image's registry until the image exists or the timeout is reached. Useful after pushing an image from CI.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRegistryWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(registryCmd)
+	registryCmd.AddCommand(registryCheckCmd)
+	registryCmd.AddCommand(registryWatchCmd)
+
+	registryCmd.PersistentFlags().StringArrayVar(&registryPlatforms, "platform", nil, "required platform os/arch[/variant] (repeatable)")
+	registryCmd.PersistentFlags().StringArrayVar(&registryLabels, "label", nil, "required image label key=value (repeatable)")
+	registryWatchCmd.Flags().DurationVar(&registryWaitTimeout, "timeout", 5*time.Minute, "maximum time to watch")
+	registryWatchCmd.Flags().DurationVar(&registryWaitInterval, "interval", 5*time.Second, "initial poll interval (later polls back off up to 30s)")
+}
+
+func runRegistryCheck(cmd *cobra.Command, args []string) error {
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "[dry-run] Would check if image %s exists\n", args[0])
+		return nil
+	}
+	verify, err := registryVerifyOptions()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	image := args[0]
+	client := registry.NewClient()
+	ok, err := client.Check(ctx, image)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "image %s not found\n", image)
+		os.Exit(1)
+	}
+	if err := client.Verify(ctx, image, verify); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✓ Image %s is available\n", image)
+	return nil
+}
+
+func runRegistryWatch(cmd *cobra.Command, args []string) error {
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "[dry-run] Would watch for image %s (timeout %s)\n", args[0], registryWaitTimeout)
+		return nil
+	}
+	verify, err := registryVerifyOptions()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	image := args[0]
+	opts := registry.WaitOptions{
+		Timeout:  registryWaitTimeout,
+		Interval: registryWaitInterval,
+		Verify:   verify,
+		Progress: waitProgress(nil, logStderr),
+	}
+	if err := registry.Wait(ctx, image, opts); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✓ Image %s is available\n", image)
+	return nil
+}
+
+// registryVerifyOptions builds the image verification options from --platform and --label.
+func registryVerifyOptions() (registry.VerifyOptions, error) {
+	opts := registry.VerifyOptions{Platforms: registryPlatforms}
+	for _, l := range registryLabels {
+		key, value, ok := strings.Cut(l, "=")
+		if !ok || key == "" {
+			return opts, fmt.Errorf("--label %q: expected key=value", l)
+		}
+		if opts.Labels == nil {
+			opts.Labels = make(map[string]string)
+		}
+		opts.Labels[key] = value
+	}
+	return opts, nil
+}
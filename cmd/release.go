@@ -10,28 +10,29 @@ import (
 	"time"
 
 	"github.com/johnewart/releasebot/internal/config"
-	"github.com/johnewart/releasebot/internal/dockerhub"
 	"github.com/johnewart/releasebot/internal/git"
-	"github.com/johnewart/releasebot/internal/github"
-	"github.com/johnewart/releasebot/internal/just"
-	"github.com/johnewart/releasebot/internal/pypi"
 	"github.com/johnewart/releasebot/internal/semver"
+	"github.com/johnewart/releasebot/internal/state"
 	"github.com/spf13/cobra"
 )
 
 var (
-	releasePrevTag     string
-	releaseBranch      string
-	releaseRemote      string
-	releaseRC          bool
-	releaseAlpha       bool
-	releaseMinor       bool
-	releaseMajor       bool
-	releaseNoTUI       bool
-	releaseConfirm     bool
-	releaseWaitTimeout time.Duration
-	releasePyPIWait    time.Duration
-	releaseDockerWait  time.Duration
+	releasePrevTag      string
+	releaseBranch       string
+	releaseRemote       string
+	releaseRC           bool
+	releaseAlpha        bool
+	releaseMinor        bool
+	releaseMajor        bool
+	releaseNoTUI        bool
+	releaseConfirm      bool
+	releaseWaitTimeout  time.Duration
+	releasePyPIWait     time.Duration
+	releaseDockerWait   time.Duration
+	releaseArtifactWait time.Duration
+	releaseResume       bool
+	releaseAuto         bool
+	releaseEdit         bool
 )
 
 var releaseCmd = &cobra.Command{
@@ -39,10 +40,22 @@ var releaseCmd = &cobra.Command{
 	Short: "Full release: changelog, commit, tag, push, wait for CI and artifacts",
 	Long: `Release figures out the previous version tag (or uses --prev-tag), generates a changelog
 from commits/PRs between that tag and the release branch, commits the changelog, creates the next
-tag (patch by default; use --release for minor, --major, --rc, --alpha), pushes branch and tags
-to the remote, waits for release workflows to complete, then checks/waits for PyPI and Docker Hub
-if configured. Uses an interactive TUI by default when run in a terminal (use --no-tui for plain
-output). Use --confirm to pause before each step and require approval to continue. Honors --dry-run.`,
+tag (patch by default; use --release for minor, --major, --rc, --alpha, or --auto to infer the
+bump from the changes), pushes branch and tags to the remote, waits for release workflows to
+complete, publishes the GitHub Release (with release.github_release), then checks/waits for PyPI,
+container images (Docker Hub, GHCR or any OCI registry), npm, crates.io, Maven and the Go module
+proxy if configured. Uses an interactive TUI
+by default when run in a terminal (use --no-tui for plain output). Use --confirm to pause before
+each step and require approval to continue. Honors --dry-run.
+
+The TUI shows the generated changelog section for review before "Commit & tag": drop entries, open
+it in $EDITOR, or regenerate it with another LLM model, then accept it to continue. Without the TUI,
+--edit opens the section in $EDITOR instead (an empty section aborts the release).
+
+Each step's outcome is recorded in .releasebot/release-state.json. If a step fails (e.g. the
+workflow wait times out after the tag was pushed), fix the problem and run 'release --resume' to
+continue from the first incomplete step with the recorded tag instead of computing a new one.
+Use 'release rollback' (or --rollback-on-failure) to undo a half-finished release instead.`,
 	RunE: runRelease,
 }
 
@@ -57,19 +70,26 @@ func init() {
 	releaseCmd.Flags().BoolVar(&releaseMajor, "major", false, "with --release, create new major version (X+1.0.0)")
 	releaseCmd.Flags().BoolVar(&releaseNoTUI, "no-tui", false, "disable TUI and use plain stderr output (default: TUI when in a terminal)")
 	releaseCmd.Flags().BoolVar(&releaseConfirm, "confirm", false, "pause before each step and require Enter to proceed (skips TUI)")
+	releaseCmd.Flags().BoolVar(&releaseEdit, "edit", false, "without the TUI, open the generated changelog section in $EDITOR before it is committed")
 	releaseCmd.Flags().DurationVar(&releaseWaitTimeout, "workflow-timeout", 30*time.Minute, "max time to wait for release workflows")
 	releaseCmd.Flags().DurationVar(&releasePyPIWait, "pypi-timeout", 10*time.Minute, "max time to wait for PyPI package")
 	releaseCmd.Flags().DurationVar(&releaseDockerWait, "docker-timeout", 10*time.Minute, "max time to wait for Docker image")
+	releaseCmd.Flags().DurationVar(&releaseArtifactWait, "artifact-timeout", 10*time.Minute, "max time to wait for each npm, crates.io, Maven and Go proxy package")
+	releaseCmd.Flags().BoolVar(&releaseAuto, "auto", false, "infer patch/minor/major from the changes since the previous release (breaking or Removed → major, Added → minor)")
+	releaseCmd.Flags().StringVar(&component, "component", "", "monorepo component (from components in config): its tags, paths and changelog output are used")
+	releaseCmd.Flags().BoolVar(&releaseResume, "resume", false, "resume an interrupted release from its first incomplete step (uses the tag recorded in .releasebot/release-state.json)")
 }
 
 // releaseParams holds resolved values for the release steps (passed to doReleaseSteps / TUI).
 type releaseParams struct {
-	ctx             context.Context
-	repoAbs         string
-	cfg             *config.Config
-	prev            string
-	branch          string
-	nextTagForRef   string
+	ctx           context.Context
+	repoAbs       string
+	cfg           *config.Config
+	prev          string
+	branch        string
+	nextTagForRef string
+	// versionTag is nextTagForRef without the component tag prefix (e.g. v1.2.3 for api/v1.2.3); used for package versions and image tags.
+	versionTag      string
 	remote          string
 	outPathAbs      string
 	outPath         string
@@ -77,6 +97,17 @@ type releaseParams struct {
 	releaseWaitTo   time.Duration
 	releasePyPITo   time.Duration
 	releaseDockerTo time.Duration
+	// releaseArtifactTo is the default wait for npm, crates, maven and goproxy steps.
+	releaseArtifactTo time.Duration
+	// stepDetail, when set (by the TUI), shows a progress line under the running step (e.g. what a wait is missing).
+	stepDetail func(detail string)
+	// reviewChangelog, when set (by the TUI, or --edit), reviews the generated changelog section before it is committed.
+	reviewChangelog changelogReviewer
+	// steps is the release pipeline (release.steps or the default pipeline).
+	steps []releaseStep
+	// state records each step's outcome (nil in dry-run); saved to statePath after every step.
+	state     *state.Release
+	statePath string
 }
 
 // releaseReporter is called after each step (step index, error if any, skipped).
@@ -86,17 +117,6 @@ type releaseReporter func(step int, err error, skipped bool)
 // releaseConfirmBeforeStep is called before each step when using --confirm. Return an error to abort.
 type releaseConfirmBeforeStep func(step int, name string) error
 
-// releaseStepNames must match the order of steps in doReleaseSteps.
-var releaseStepNames = []string{
-	"Just targets",
-	"Generate changelog",
-	"Commit & tag",
-	"Push to remote",
-	"Wait for workflows",
-	"PyPI",
-	"Docker Hub",
-}
-
 func runRelease(cmd *cobra.Command, args []string) error {
 	if releaseRC && releaseAlpha {
 		return fmt.Errorf("cannot use both --rc and --alpha")
@@ -107,6 +127,12 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	if releaseMajor && !releaseMinor {
 		return fmt.Errorf("--major must be used with --release")
 	}
+	if releaseAuto && (releaseRC || releaseAlpha || releaseMinor || releaseMajor) {
+		return fmt.Errorf("--auto picks the bump itself; cannot combine with --rc, --alpha, --release or --major")
+	}
+	if releaseResume && (releaseRC || releaseAlpha || releaseMinor || releaseMajor || releaseAuto || releasePrevTag != "") {
+		return fmt.Errorf("--resume reuses the recorded tag; cannot combine with --rc, --alpha, --release, --major, --auto or --prev-tag")
+	}
 
 	ctx := context.Background()
 	repoAbs, err := filepath.Abs(repoPath)
@@ -114,6 +140,31 @@ func runRelease(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("repo path: %w", err)
 	}
 
+	// --resume: reuse the tag, branch and remote recorded by the interrupted release.
+	statePath := filepath.Join(repoAbs, state.DefaultFile)
+	var resumed *state.Release
+	if releaseResume {
+		resumed, err = state.Load(statePath)
+		if err != nil {
+			return err
+		}
+		if resumed == nil {
+			return fmt.Errorf("no release in progress to resume (%s not found)", state.DefaultFile)
+		}
+		if releaseBranch != "" && releaseBranch != resumed.Branch {
+			return fmt.Errorf("--branch %s differs from the branch %s of the release being resumed", releaseBranch, resumed.Branch)
+		}
+	} else if !dryRun {
+		// A new release would overwrite the state of an unfinished one, losing what resume and rollback need.
+		existing, err := state.Load(statePath)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("release %s is still in progress (%s): run 'releasebot release --resume' to finish it or 'releasebot release rollback' to undo it", existing.Tag, state.DefaultFile)
+		}
+	}
+
 	configPath := cfgFile
 	if !filepath.IsAbs(configPath) {
 		configPath = filepath.Join(repoAbs, configPath)
@@ -123,40 +174,56 @@ func runRelease(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	cfg.Resolve(repoAbs)
+	if resumed != nil && component == "" {
+		component = resumed.Component
+	}
+	if err := cfg.UseComponent(component); err != nil {
+		return err
+	}
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return err
+	}
 
-	// Resolve branch (current or --branch)
+	// Resolve branch (current or --branch; recorded branch when resuming)
 	branch := releaseBranch
+	if resumed != nil {
+		branch = resumed.Branch
+	}
 	if branch == "" {
 		branch, err = git.CurrentBranch(ctx, repoAbs)
 		if err != nil {
 			return err
 		}
 	}
-	// If --branch was set and we're not on it, checkout (skip in dry-run)
-	if releaseBranch != "" && !dryRun {
+	// If --branch was set (or resuming) and we're not on it, checkout (skip in dry-run)
+	if (releaseBranch != "" || resumed != nil) && !dryRun {
 		current, err := git.CurrentBranch(ctx, repoAbs)
 		if err != nil {
 			return err
 		}
-		if current != releaseBranch {
-			if err := git.Checkout(ctx, repoAbs, releaseBranch); err != nil {
+		if current != branch {
+			if err := git.Checkout(ctx, repoAbs, branch); err != nil {
 				return err
 			}
-			fmt.Fprintf(os.Stderr, "✓ Checked out %s\n", releaseBranch)
+			fmt.Fprintf(os.Stderr, "✓ Checked out %s\n", branch)
 		}
 	}
 
-	// Resolve previous tag (--prev-tag or latest semver)
+	// Resolve previous tag (--prev-tag or latest semver; recorded tag when resuming)
 	prev := releasePrevTag
+	if resumed != nil {
+		prev = resumed.PrevTag
+	}
 	if prev == "" {
 		prev = cfg.PreviousReleaseTag
 	}
 	if prev == "" {
-		tags, err := git.ListTags(ctx, repoAbs)
+		tags, err := componentTags(ctx, repoAbs, cfg)
 		if err != nil {
 			return err
 		}
-		prev = semver.LatestStableTag(tags)
+		prev = semver.AddTagPrefix(cfg.TagPrefix(), semver.LatestStableTagScheme(tags, scheme))
 		if prev == "" {
 			return fmt.Errorf("could not determine previous release tag: use --prev-tag, set previous_release_tag in config, or ensure repo has semver tags (e.g. v1.0.0)")
 		}
@@ -165,32 +232,52 @@ func runRelease(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Next tag (same logic as tag next)
-	tags, err := git.ListTags(ctx, repoAbs)
-	if err != nil {
-		return err
-	}
-	nextTag := semver.NextFromTags(tags, releaseRC, releaseAlpha, releaseMinor, releaseMajor)
-	// Ensure tag has 'v' for push (NextFromTags returns "v1.2.3" for stable, "1.2.3rc0" for rc)
-	nextTagForRef := nextTag
-	if !strings.HasPrefix(nextTag, "v") && (releaseRC || releaseAlpha) {
-		// keep as-is for rc/alpha
-	} else if !strings.HasPrefix(nextTag, "v") {
-		nextTagForRef = "v" + nextTag
+	// Next tag (same logic as tag next); when resuming, the recorded tag is reused as-is.
+	var nextTagForRef string
+	if resumed != nil {
+		nextTagForRef = resumed.Tag
+	} else {
+		tags, err := componentTags(ctx, repoAbs, cfg)
+		if err != nil {
+			return err
+		}
+		minor, major := releaseMinor, releaseMajor
+		if releaseAuto {
+			var reason string
+			minor, major, reason, err = autoBump(ctx, cfg, repoAbs, prev, branch)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Auto bump %s\n", reason)
+		}
+		nextTag := semver.NextFromTagsScheme(tags, scheme, releaseRC, releaseAlpha, minor, major)
+		// Ensure tag has 'v' for push (NextFromTags returns "v1.2.3" for stable, "1.2.3rc0" for rc)
+		nextTagForRef = nextTag
+		if !strings.HasPrefix(nextTag, "v") && (releaseRC || releaseAlpha) {
+			// keep as-is for rc/alpha
+		} else if !strings.HasPrefix(nextTag, "v") {
+			nextTagForRef = "v" + nextTag
+		}
+		nextTagForRef = semver.AddTagPrefix(cfg.TagPrefix(), nextTagForRef)
 	}
 
 	// Remote
 	remote := releaseRemote
-	if remote == "" && cfg.Release != nil && cfg.Release.Remote != "" {
-		remote = cfg.Release.Remote
+	if resumed != nil && remote == "" {
+		remote = resumed.Remote
 	}
 	if remote == "" {
-		remote = "origin"
+		remote = defaultRemote(cfg)
 	}
 	if _, err := git.RemoteURL(ctx, repoAbs, remote); err != nil {
 		return fmt.Errorf("remote %s: %w", remote, err)
 	}
 
+	steps, err := buildReleasePipeline(cfg)
+	if err != nil {
+		return err
+	}
+
 	// Changelog output path (relative to repo)
 	outPath := "CHANGELOG.md"
 	if cfg.Changelog != nil && cfg.Changelog.Output != "" {
@@ -202,19 +289,37 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	}
 
 	params := &releaseParams{
-		ctx:             ctx,
-		repoAbs:         repoAbs,
-		cfg:             cfg,
-		prev:            prev,
-		branch:          branch,
-		nextTagForRef:   nextTagForRef,
-		remote:          remote,
-		outPathAbs:      outPathAbs,
-		outPath:         outPath,
-		dryRun:          dryRun,
-		releaseWaitTo:   releaseWaitTimeout,
-		releasePyPITo:   releasePyPIWait,
-		releaseDockerTo: releaseDockerWait,
+		ctx:               ctx,
+		repoAbs:           repoAbs,
+		cfg:               cfg,
+		prev:              prev,
+		branch:            branch,
+		nextTagForRef:     nextTagForRef,
+		versionTag:        strings.TrimPrefix(nextTagForRef, cfg.TagPrefix()),
+		remote:            remote,
+		outPathAbs:        outPathAbs,
+		outPath:           outPath,
+		dryRun:            dryRun,
+		releaseWaitTo:     releaseWaitTimeout,
+		releasePyPITo:     releasePyPIWait,
+		releaseDockerTo:   releaseDockerWait,
+		releaseArtifactTo: releaseArtifactWait,
+		steps:             steps,
+		statePath:         statePath,
+	}
+	if !dryRun {
+		params.state = resumed
+		if params.state == nil {
+			params.state = state.New(nextTagForRef, prev, branch, remote)
+			params.state.Component = component
+			if sha, err := git.RevParse(ctx, repoAbs, "HEAD"); err == nil {
+				params.state.BaseSHA = sha
+			}
+		}
+	}
+
+	if releaseEdit {
+		params.reviewChangelog = editChangelogSection
 	}
 
 	// --confirm: run without TUI and prompt before each step.
@@ -230,8 +335,8 @@ func runRelease(cmd *cobra.Command, args []string) error {
 	// Plain output path (no TUI): dry-run or --no-tui or not a TTY
 	if dryRun {
 		fmt.Fprintf(os.Stderr, "✓ Previous tag %s validated\n", prev)
-		if cfg.Justfile != nil && len(cfg.Justfile.Targets) > 0 {
-			fmt.Fprintf(os.Stderr, "✓ Just targets completed: %v\n", cfg.Justfile.Targets)
+		for _, line := range githubDryRunReport(ctx, cfg, repoAbs, remote) {
+			fmt.Fprintf(os.Stderr, "✓ %s\n", line)
 		}
 		usePRsRes, useHistoryRes := resolveChangelogSource(cfg, usePRs, useHistory)
 		src, err := gatherChangelogSource(ctx, cfg, repoAbs, prev, branch, 0, usePRsRes, useHistoryRes, nil, nil)
@@ -243,314 +348,93 @@ func runRelease(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Fprintf(os.Stderr, "✓ Found %d commit(s) between %s and %s\n", len(src.Commits), prev, branch)
 		}
-		fmt.Fprintf(os.Stderr, "✓ Changelog written to %s\n", outPathAbs)
-		fmt.Fprintf(os.Stderr, "✓ Committed and tagged %s\n", nextTagForRef)
-		fmt.Fprintf(os.Stderr, "✓ Pushed %s to %s\n", branch, remote)
-		fmt.Fprintf(os.Stderr, "✓ Pushed tag %s to %s\n", nextTagForRef, remote)
-		fmt.Fprintf(os.Stderr, "✓ All release workflow(s) completed\n")
-		if cfg.Release != nil && cfg.Release.PyPIPackage != "" {
-			pkgVersion := strings.TrimPrefix(nextTagForRef, "v")
-			fmt.Fprintf(os.Stderr, "✓ Package %s==%s is available on PyPI\n", cfg.Release.PyPIPackage, pkgVersion)
-		}
-		if cfg.Release != nil && cfg.Release.DockerImage != "" {
-			fmt.Fprintf(os.Stderr, "✓ Image %s:%s is available on Docker Hub\n", cfg.Release.DockerImage, nextTagForRef)
+		for _, step := range steps {
+			for _, line := range releaseStepPlanLines(params, step) {
+				fmt.Fprintf(os.Stderr, "✓ %s\n", line)
+			}
 		}
 		fmt.Fprintf(os.Stderr, "✓ Release %s complete (dry-run)\n", nextTagForRef)
 		return nil
 	}
-	return doReleaseSteps(params, nil, nil)
+	return withRollbackOnFailure(params, doReleaseSteps(params, nil, nil), nil)
 }
 
 // runReleaseConfirm runs the release with a prompt before each step (--confirm). Uses stderr/stdin.
 func runReleaseConfirm(params *releaseParams) error {
 	reader := bufio.NewReader(os.Stdin)
 	confirm := func(step int, name string) error {
-		fmt.Fprintf(os.Stderr, "\nStep %d/%d: %s\n", step+1, len(releaseStepNames), name)
+		fmt.Fprintf(os.Stderr, "\nStep %d/%d: %s\n", step+1, len(params.steps), name)
 		fmt.Fprintf(os.Stderr, "  Press Enter to run this step, or Ctrl+C to abort: ")
 		_, err := reader.ReadString('\n')
 		return err
 	}
-	return doReleaseSteps(params, nil, confirm)
+	return withRollbackOnFailure(params, doReleaseSteps(params, nil, confirm), nil)
 }
 
-// doReleaseSteps runs the 7 release steps. If report is non-nil, it's called after each step (for TUI);
-// if nil, progress is printed to stderr. If confirmBeforeStep is non-nil, it is called before each step
-// and returning an error aborts the release.
-func doReleaseSteps(params *releaseParams, report releaseReporter, confirmBeforeStep releaseConfirmBeforeStep) error {
-	ctx := params.ctx
-	repoAbs := params.repoAbs
-	cfg := params.cfg
-	branch := params.branch
-	nextTagForRef := params.nextTagForRef
-	remote := params.remote
-	outPathAbs := params.outPathAbs
-	outPath := params.outPath
-
-	// 0. Just targets
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(0, releaseStepNames[0]); err != nil {
-			return fmt.Errorf("aborted at step 1: %w", err)
-		}
-	}
-	hasJust := cfg.Justfile != nil && len(cfg.Justfile.Targets) > 0
-	if hasJust {
-		workDir := repoAbs
-		if cfg.Justfile.WorkingDir != "" {
-			workDir = cfg.Justfile.WorkingDir
-		}
-		result, err := just.Runner(workDir, cfg.Justfile.Targets)
-		if err != nil {
-			if report != nil {
-				report(0, err, false)
-			}
-			return fmt.Errorf("just: %w", err)
-		}
-		if !result.Success() {
-			err := fmt.Errorf("just target(s) failed: %v", result.Failed)
-			if report != nil {
-				report(0, err, false)
-			}
-			return err
-		}
-		if report != nil {
-			report(0, nil, false)
-		} else {
-			fmt.Fprintf(os.Stderr, "✓ Just targets completed: %v\n", cfg.Justfile.Targets)
-		}
-	} else if report != nil {
-		report(0, nil, true)
-	}
-
-	// 1. Generate changelog
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(1, releaseStepNames[1]); err != nil {
-			return fmt.Errorf("aborted at step 2: %w", err)
-		}
-	}
-	if err := generateChangelogSection(ctx, cfg, repoAbs, params.prev, branch, nextTagForRef, outPathAbs, 0, usePRs, useHistory, nil, nil, nil, nil); err != nil {
-		if report != nil {
-			report(1, err, false)
-		}
-		return fmt.Errorf("changelog: %w", err)
-	}
-	if report != nil {
-		report(1, nil, false)
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Changelog written to %s\n", outPathAbs)
-	}
-
-	// 2. Git add, commit, tag
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(2, releaseStepNames[2]); err != nil {
-			return fmt.Errorf("aborted at step 3: %w", err)
-		}
-	}
-	changelogRel, err := filepath.Rel(repoAbs, outPathAbs)
-	if err != nil {
-		changelogRel = outPath
-	}
-	if err := git.Add(ctx, repoAbs, changelogRel); err != nil {
-		if report != nil {
-			report(2, err, false)
-		}
-		return err
-	}
-	if err := git.CreateCommit(ctx, repoAbs, "changelog: release "+nextTagForRef); err != nil {
-		if report != nil {
-			report(2, err, false)
-		}
-		return err
-	}
-	if err := git.CreateTag(ctx, repoAbs, nextTagForRef, "Release "+nextTagForRef); err != nil {
-		if report != nil {
-			report(2, err, false)
-		}
-		return err
-	}
-	if report != nil {
-		report(2, nil, false)
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Committed and tagged %s\n", nextTagForRef)
-	}
-
-	// 3. Push branch and tag
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(3, releaseStepNames[3]); err != nil {
-			return fmt.Errorf("aborted at step 4: %w", err)
-		}
-	}
-	if err := git.Push(ctx, repoAbs, remote, "refs/heads/"+branch); err != nil {
-		if report != nil {
-			report(3, err, false)
-		}
+// withRollbackOnFailure rolls back the release when err is non-nil and --rollback-on-failure is set.
+// report is passed to rollbackAfterFailure (nil prints to stderr). Returns err, annotated with the rollback outcome.
+func withRollbackOnFailure(params *releaseParams, err error, report func(desc string, err error)) error {
+	if err == nil || !releaseRollbackOnFailure || params.state == nil {
 		return err
 	}
-	if err := git.Push(ctx, repoAbs, remote, "refs/tags/"+nextTagForRef); err != nil {
-		if report != nil {
-			report(3, err, false)
-		}
-		return err
+	if report == nil {
+		fmt.Fprintf(os.Stderr, "Rolling back release %s...\n", params.nextTagForRef)
 	}
-	if report != nil {
-		report(3, nil, false)
-	} else {
-		fmt.Fprintf(os.Stderr, "✓ Pushed %s to %s\n", branch, remote)
-		fmt.Fprintf(os.Stderr, "✓ Pushed tag %s to %s\n", nextTagForRef, remote)
+	if rbErr := rollbackAfterFailure(params, report); rbErr != nil {
+		return fmt.Errorf("%w (%v)", err, rbErr)
 	}
+	return fmt.Errorf("%w (release %s rolled back)", err, params.nextTagForRef)
+}
 
-	// 4. Wait for release workflows
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(4, releaseStepNames[4]); err != nil {
-			return fmt.Errorf("aborted at step 5: %w", err)
-		}
-	}
-	sha, err := git.RevParse(ctx, repoAbs, nextTagForRef)
-	if err != nil {
-		if report != nil {
-			report(4, err, false)
+// doReleaseSteps runs the release steps in order. If report is non-nil, it's called after each step (for TUI);
+// if nil, progress is printed to stderr. If confirmBeforeStep is non-nil, it is called before each step
+// and returning an error aborts the release. When params.state is set, steps already completed in the
+// state are skipped (resume) and each step's outcome is saved to params.statePath.
+func doReleaseSteps(params *releaseParams, report releaseReporter, confirmBeforeStep releaseConfirmBeforeStep) error {
+	logf := func(format string, args ...interface{}) {
+		if report == nil {
+			fmt.Fprintf(os.Stderr, format, args...)
 		}
-		return fmt.Errorf("resolve tag to SHA: %w", err)
 	}
-	owner, repoName := "", ""
-	if cfg.GitHub != nil && cfg.GitHub.Owner != "" && cfg.GitHub.Repo != "" {
-		owner = cfg.GitHub.Owner
-		repoName = cfg.GitHub.Repo
-	} else {
-		remoteURL, err := git.RemoteURL(ctx, repoAbs, remote)
-		if err != nil {
+	st := params.state
+	for i, step := range params.steps {
+		name := step.name
+		if st != nil && st.Completed(name) {
 			if report != nil {
-				report(4, err, false)
+				report(i, nil, st.Step(name).Status == state.StepSkipped)
+			} else {
+				fmt.Fprintf(os.Stderr, "✓ %s already completed; skipping\n", name)
 			}
-			return err
+			continue
 		}
-		owner, repoName, err = git.ParseGitHubOwnerRepo(remoteURL)
-		if err != nil {
-			if report != nil {
-				report(4, err, false)
+		if confirmBeforeStep != nil {
+			if err := confirmBeforeStep(i, name); err != nil {
+				return fmt.Errorf("aborted at step %d: %w", i+1, err)
 			}
-			return fmt.Errorf("github remote: %w", err)
 		}
-	}
-	token := ""
-	if cfg.GitHub != nil && cfg.GitHub.Token != "" {
-		token = cfg.GitHub.Token
-	}
-	if token == "" {
-		token = os.Getenv("GITHUB_TOKEN")
-	}
-	if token == "" {
-		if report != nil {
-			report(4, nil, true)
-		} else {
-			fmt.Fprintf(os.Stderr, "warning: no GITHUB_TOKEN; skipping workflow wait\n")
-		}
-	} else {
-		gh := github.NewClient(ctx, token, owner, repoName)
-		tagPushTriggers, _ := github.WorkflowsTriggeredByTag(repoAbs, nextTagForRef)
-		deadline := time.Now().Add(params.releaseWaitTo)
-		pollInterval := 15 * time.Second
-		workflowsDone := false
-		for time.Now().Before(deadline) {
-			runs, err := gh.ListWorkflowRunsForCommit(ctx, sha)
-			if err != nil {
-				if report != nil {
-					report(4, err, false)
-				}
-				return fmt.Errorf("list workflow runs: %w", err)
-			}
-			waitedRuns := runs
-			if len(tagPushTriggers) > 0 {
-				waitedRuns = github.RunsForTagPushWorkflows(runs, tagPushTriggers)
-			}
-			if len(waitedRuns) == 0 {
-				if report == nil {
-					fmt.Fprintf(os.Stderr, "Waiting for release workflows... (next check in %s)\n", pollInterval)
-				}
-				time.Sleep(pollInterval)
-				continue
-			}
-			allSeen := len(tagPushTriggers) == 0 || len(waitedRuns) >= len(tagPushTriggers)
-			if allSeen && github.AllRunsFinished(waitedRuns) {
-				if github.AnyRunFailed(waitedRuns) {
-					err := fmt.Errorf("one or more release workflows failed")
-					if report != nil {
-						report(4, err, false)
-					}
-					return err
-				}
-				workflowsDone = true
-				break
+		skipped, err := step.run(params, step.cfg, logf)
+		if st != nil {
+			st.Record(name, step.cfg.Type, skipped, err)
+			if saveErr := st.Save(params.statePath); saveErr != nil {
+				logf("warning: %v\n", saveErr)
 			}
-			if report == nil {
-				fmt.Fprintf(os.Stderr, "Waiting for workflows... (next check in %s)\n", pollInterval)
-			}
-			time.Sleep(pollInterval)
-		}
-		if !workflowsDone {
-			err := fmt.Errorf("timeout waiting for release workflows")
-			if report != nil {
-				report(4, err, false)
-			}
-			return err
 		}
 		if report != nil {
-			report(4, nil, false)
-		} else {
-			fmt.Fprintf(os.Stderr, "✓ All release workflow(s) completed\n")
-		}
-	}
-
-	// 5. PyPI wait
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(5, releaseStepNames[5]); err != nil {
-			return fmt.Errorf("aborted at step 6: %w", err)
+			report(i, err, skipped)
 		}
-	}
-	if cfg.Release != nil && cfg.Release.PyPIPackage != "" {
-		pkgVersion := strings.TrimPrefix(nextTagForRef, "v")
-		opts := pypi.WaitOptions{Timeout: params.releasePyPITo, Interval: 5 * time.Second}
-		if err := pypi.Wait(ctx, cfg.Release.PyPIPackage, pkgVersion, opts); err != nil {
-			if report != nil {
-				report(5, err, false)
+		if err != nil {
+			if report == nil && st != nil && !releaseRollbackOnFailure {
+				fmt.Fprintf(os.Stderr, "Release state saved to %s; fix the problem and run 'releasebot release --resume'\n", params.statePath)
 			}
-			return fmt.Errorf("pypi wait: %w", err)
-		}
-		if report != nil {
-			report(5, nil, false)
-		} else {
-			fmt.Fprintf(os.Stderr, "✓ Package %s==%s is available on PyPI\n", cfg.Release.PyPIPackage, pkgVersion)
-		}
-	} else if report != nil {
-		report(5, nil, true)
-	}
-
-	// 6. Docker Hub wait
-	if confirmBeforeStep != nil {
-		if err := confirmBeforeStep(6, releaseStepNames[6]); err != nil {
-			return fmt.Errorf("aborted at step 7: %w", err)
+			return err
 		}
 	}
-	if cfg.Release != nil && cfg.Release.DockerImage != "" {
-		imageRef := cfg.Release.DockerImage + ":" + nextTagForRef
-		opts := dockerhub.WaitOptions{Timeout: params.releaseDockerTo, Interval: 5 * time.Second}
-		if err := dockerhub.Wait(ctx, imageRef, opts); err != nil {
-			if report != nil {
-				report(6, err, false)
-			}
-			return fmt.Errorf("docker hub wait: %w", err)
+	if st != nil {
+		if err := state.Remove(params.statePath); err != nil {
+			logf("warning: %v\n", err)
 		}
-		if report != nil {
-			report(6, nil, false)
-		} else {
-			fmt.Fprintf(os.Stderr, "✓ Image %s is available on Docker Hub\n", imageRef)
-		}
-	} else if report != nil {
-		report(6, nil, true)
-	}
-
-	if report == nil {
-		fmt.Fprintf(os.Stderr, "✓ Release %s complete\n", nextTagForRef)
 	}
+	logf("✓ Release %s complete\n", params.nextTagForRef)
 	return nil
 }
 
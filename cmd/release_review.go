@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/config"
+)
+
+// changelogReviewer reviews the generated changelog section before it is committed (the TUI review stage,
+// or $EDITOR with --edit). model is the LLM model that wrote it; regenerate writes a new section with
+// another model. Returns the section to commit, or an error to stop the release.
+type changelogReviewer func(section, model string, regenerate func(model string) (string, error)) (string, error)
+
+// errEmptyChangelog is returned when the changelog section is emptied in the editor, which aborts the release
+// (like an empty commit message in git).
+var errEmptyChangelog = errors.New("empty changelog section; release aborted")
+
+// editChangelogSection is the --edit reviewer: it opens the section in the editor and returns what was saved.
+func editChangelogSection(section, model string, regenerate func(model string) (string, error)) (string, error) {
+	f, err := os.CreateTemp("", "releasebot-changelog-*.md")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.WriteString(section); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	cmd := editorCommand(path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor: %w", err)
+	}
+	return readEditedSection(path)
+}
+
+// editorCommand returns the command that opens path in $VISUAL, else $EDITOR, else vi. The editor is run by
+// the shell, as git does, so it can carry arguments (e.g. "code --wait").
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	return exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+}
+
+// readEditedSection reads the section saved in the editor; errEmptyChangelog when it was emptied.
+func readEditedSection(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	section := strings.TrimSpace(string(data))
+	if section == "" {
+		return "", errEmptyChangelog
+	}
+	return section + "\n", nil
+}
+
+// withLLMModel returns a copy of cfg whose changelog LLM uses model (the provider's default when empty) with
+// the configured provider and settings. Per-PR summaries are not cached in the copy: the cache is not keyed by
+// model and would return the previous model's summaries.
+func withLLMModel(cfg *config.Config, model string) (*config.Config, error) {
+	provider, _, baseURL := resolveLLMConfig(cfg)
+	if provider == "" {
+		return nil, fmt.Errorf("no LLM configured to regenerate with (set changelog.llm or llm in config)")
+	}
+	var llm config.LLMConfig
+	if cfg.Changelog != nil && cfg.Changelog.LLM != nil {
+		llm = *cfg.Changelog.LLM
+	} else if cfg.LLM != nil {
+		llm = *cfg.LLM
+	}
+	llm.Provider, llm.Model, llm.BaseURL = provider, strings.TrimSpace(model), baseURL
+	noCache := false
+	llm.CacheLLMSummaries = &noCache
+	var cl config.ChangelogConfig
+	if cfg.Changelog != nil {
+		cl = *cfg.Changelog
+	}
+	cl.LLM = &llm
+	c := *cfg
+	c.Changelog = &cl
+	return &c, nil
+}
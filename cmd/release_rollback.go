@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseRollbackOnFailure bool
+	rollbackResetBranch      bool
+)
+
+var releaseRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Undo a half-finished release",
+	Long: `Rollback reads .releasebot/release-state.json and undoes the completed steps of an
+interrupted release in reverse order: deletes the GitHub (or GitLab) Release it created, deletes the
+remote and local tag, resets the changelog commit, and restores the previous changelog contents.
+Working tree changes other than the changelog are kept. The pushed release branch is left alone
+unless --reset-branch is given, which moves it back (--force-with-lease) if it still points at the
+release commit. Every action is attempted even if one fails; the state file is kept until all
+succeed. Honors --dry-run.`,
+	RunE: runReleaseRollback,
+}
+
+func init() {
+	releaseCmd.AddCommand(releaseRollbackCmd)
+	releaseRollbackCmd.Flags().BoolVar(&rollbackResetBranch, "reset-branch", false, "also force-push the remote release branch back to where the release started (if it still points at the release commit)")
+	releaseCmd.Flags().BoolVar(&releaseRollbackOnFailure, "rollback-on-failure", false, "when a step fails, undo the completed steps (tag, changelog commit, changelog file)")
+}
+
+// rollbackAction is one undo action (e.g. "Delete tag v1.2.3 on origin").
+type rollbackAction struct {
+	desc string
+	run  func() error
+}
+
+// planRollback returns the undo actions for the steps recorded in st, latest step first.
+// Steps that failed part-way are included; each action checks the actual repo state so it is safe to re-run.
+// cfg is used to reach GitHub or GitLab when the release created a release there; when the client cannot be
+// built the action fails with that error. The remote branch is only moved back when resetBranch is set.
+func planRollback(ctx context.Context, cfg *config.Config, repoAbs string, st *state.Release, resetBranch bool) []rollbackAction {
+	var actions []rollbackAction
+	if st.RanType(config.StepGitHubRelease) && st.GitHubReleaseID != 0 {
+		action := rollbackAction{desc: "Delete GitHub Release " + st.Tag}
+		gh, err := releaseGitHubClient(ctx, cfg, repoAbs, st.Remote)
+		switch {
+		case err != nil:
+			action.run = func() error { return err }
+		case gh == nil:
+			action.run = func() error { return fmt.Errorf("GitHub credentials required: %s", githubCredentialsHint) }
+		default:
+			action.run = func() error { return gh.DeleteRelease(ctx, st.GitHubReleaseID) }
+		}
+		actions = append(actions, action)
+	}
+	if st.RanType(config.StepGitHubRelease) && st.GitLabReleaseTag != "" {
+		action := rollbackAction{desc: "Delete GitLab Release " + st.GitLabReleaseTag}
+		gl, ok, err := releaseGitLabClient(ctx, cfg, repoAbs, st.Remote)
+		switch {
+		case err != nil:
+			action.run = func() error { return err }
+		case !ok:
+			action.run = func() error { return fmt.Errorf("remote %s is not on GitLab", st.Remote) }
+		case gl == nil:
+			action.run = func() error { return fmt.Errorf("GitLab token required: set GITLAB_TOKEN or gitlab.token in config") }
+		default:
+			action.run = func() error { return gl.DeleteRelease(ctx, st.GitLabReleaseTag) }
+		}
+		actions = append(actions, action)
+	}
+	if st.RanType(config.StepPush) {
+		tagRef := "refs/tags/" + st.Tag
+		if sha, err := git.RemoteRefSHA(ctx, repoAbs, st.Remote, tagRef); err == nil && sha != "" {
+			actions = append(actions, rollbackAction{
+				desc: fmt.Sprintf("Delete tag %s on %s", st.Tag, st.Remote),
+				run:  func() error { return git.DeleteRemoteRef(ctx, repoAbs, st.Remote, tagRef) },
+			})
+		}
+		branchRef := "refs/heads/" + st.Branch
+		if resetBranch && st.CommitSHA != "" && st.BaseSHA != "" {
+			if sha, err := git.RemoteRefSHA(ctx, repoAbs, st.Remote, branchRef); err == nil && sha == st.CommitSHA {
+				actions = append(actions, rollbackAction{
+					desc: fmt.Sprintf("Reset %s on %s to %s", st.Branch, st.Remote, shortSHA(st.BaseSHA)),
+					run: func() error {
+						return git.ForcePushWithLease(ctx, repoAbs, st.Remote, st.Branch, st.BaseSHA, st.CommitSHA)
+					},
+				})
+			}
+		}
+	}
+	if st.RanType(config.StepCommitTag) {
+		if _, err := git.ValidateTag(ctx, repoAbs, st.Tag); err == nil {
+			actions = append(actions, rollbackAction{
+				desc: "Delete local tag " + st.Tag,
+				run:  func() error { return git.DeleteTag(ctx, repoAbs, st.Tag) },
+			})
+		}
+		if st.CommitSHA != "" && st.BaseSHA != "" {
+			if head, err := git.RevParse(ctx, repoAbs, "HEAD"); err == nil && head == st.CommitSHA {
+				actions = append(actions, rollbackAction{
+					desc: "Reset changelog commit (back to " + shortSHA(st.BaseSHA) + ")",
+					run:  func() error { return git.ResetMixed(ctx, repoAbs, st.BaseSHA) },
+				})
+			}
+		}
+	}
+	if st.RanType(config.StepChangelog) && st.Changelog != "" {
+		path := filepath.Join(repoAbs, st.Changelog)
+		if st.ChangelogExisted {
+			actions = append(actions, rollbackAction{
+				desc: "Restore previous " + st.Changelog,
+				run:  func() error { return os.WriteFile(path, []byte(st.PrevChangelog), 0644) },
+			})
+		} else {
+			actions = append(actions, rollbackAction{
+				desc: "Remove generated " + st.Changelog,
+				run: func() error {
+					if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+						return err
+					}
+					return nil
+				},
+			})
+		}
+	}
+	return actions
+}
+
+// runRollbackActions runs every action in order, calling report after each (if non-nil). A failed action
+// does not stop the rest (a rejected remote tag deletion must not leave the local commit behind); the
+// errors are returned together.
+func runRollbackActions(actions []rollbackAction, report func(i int, err error)) error {
+	var errs []error
+	for i, a := range actions {
+		err := a.run()
+		if report != nil {
+			report(i, err)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rollback: %s: %w", a.desc, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rollbackAfterFailure undoes the release recorded in params.state after a failed step (--rollback-on-failure).
+// report is called with each action's description and result; if nil, progress is printed to stderr.
+// The remote branch is not moved back. On success the state file is removed; it is kept when an action fails.
+func rollbackAfterFailure(params *releaseParams, report func(desc string, err error)) error {
+	if params.state == nil {
+		return nil
+	}
+	actions := planRollback(params.ctx, params.cfg, params.repoAbs, params.state, false)
+	err := runRollbackActions(actions, func(i int, err error) {
+		if report != nil {
+			report(actions[i].desc, err)
+		} else if err == nil {
+			fmt.Fprintf(os.Stderr, "↩ %s\n", actions[i].desc)
+		} else {
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", actions[i].desc, err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return state.Remove(params.statePath)
+}
+
+func runReleaseRollback(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	repoAbs, err := filepath.Abs(repoPath)
+	if err != nil {
+		return fmt.Errorf("repo path: %w", err)
+	}
+	statePath := filepath.Join(repoAbs, state.DefaultFile)
+	st, err := state.Load(statePath)
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		return fmt.Errorf("no release in progress to roll back (%s not found)", state.DefaultFile)
+	}
+	configPath := cfgFile
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(repoAbs, configPath)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	cfg.Resolve(repoAbs)
+	actions := planRollback(ctx, cfg, repoAbs, st, rollbackResetBranch)
+	if len(actions) == 0 {
+		fmt.Fprintf(os.Stderr, "Nothing to roll back for %s\n", st.Tag)
+		if dryRun {
+			return nil
+		}
+		return state.Remove(statePath)
+	}
+	if dryRun {
+		for _, a := range actions {
+			fmt.Fprintf(os.Stderr, "[dry-run] Would %s\n", lowerFirst(a.desc))
+		}
+		return nil
+	}
+	if isTerminal(os.Stdout) && !noTUI {
+		steps := make([]string, len(actions))
+		for i, a := range actions {
+			steps[i] = a.desc
+		}
+		err = RunTaskTUI(" releasebot  rollback "+st.Tag+" ", steps, func(ch chan<- interface{}) {
+			err := runRollbackActions(actions, func(i int, err error) {
+				ch <- taskStepResultMsg{Step: i, Err: err}
+			})
+			ch <- taskDoneMsg{Err: err}
+		})
+	} else {
+		err = runRollbackActions(actions, func(i int, err error) {
+			if err == nil {
+				fmt.Fprintf(os.Stderr, "✓ %s\n", actions[i].desc)
+			} else {
+				fmt.Fprintf(os.Stderr, "✗ %s: %v\n", actions[i].desc, err)
+			}
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if err := state.Remove(statePath); err != nil {
+		return err
+	}
+	if !isTerminal(os.Stdout) || noTUI {
+		fmt.Fprintf(os.Stderr, "✓ Release %s rolled back\n", st.Tag)
+	}
+	return nil
+}
+
+// shortSHA returns the first 7 characters of sha.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// lowerFirst lowercases the first letter of s (for "Would delete ..." dry-run lines).
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
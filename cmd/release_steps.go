@@ -0,0 +1,817 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+	"github.com/johnewart/releasebot/internal/changelog"
+	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/github"
+	"github.com/johnewart/releasebot/internal/just"
+	"github.com/johnewart/releasebot/internal/pypi"
+	"github.com/johnewart/releasebot/internal/registry"
+	"github.com/johnewart/releasebot/internal/semver"
+	"github.com/johnewart/releasebot/internal/slack"
+)
+
+// releaseStepFunc runs one release step with its release.steps options. logf prints progress in plain mode
+// and is a no-op under the TUI. Returns skipped=true when the step had nothing to do (e.g. no just targets configured).
+type releaseStepFunc func(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (skipped bool, err error)
+
+// releaseStep is one entry of the resolved release pipeline.
+type releaseStep struct {
+	name string
+	cfg  config.ReleaseStepConfig
+	run  releaseStepFunc
+}
+
+// releaseStepTypes maps release.steps types to their implementation.
+var releaseStepTypes = map[string]releaseStepFunc{
+	config.StepJust:          releaseStepJust,
+	config.StepChangelog:     releaseStepChangelog,
+	config.StepCommitTag:     releaseStepCommitTag,
+	config.StepPush:          releaseStepPush,
+	config.StepWaitWorkflows: releaseStepWaitWorkflows,
+	config.StepGitHubRelease: releaseStepGitHubRelease,
+	config.StepPyPI:          releaseStepPyPI,
+	config.StepDockerHub:     releaseStepDockerHub,
+	config.StepNpm:           releaseStepArtifact,
+	config.StepCrates:        releaseStepArtifact,
+	config.StepMaven:         releaseStepArtifact,
+	config.StepGoProxy:       releaseStepArtifact,
+	config.StepShell:         releaseStepShell,
+	config.StepNotify:        releaseStepNotify,
+}
+
+// defaultReleaseStepName returns the display name for a step without an explicit name.
+func defaultReleaseStepName(sc config.ReleaseStepConfig) string {
+	switch sc.Type {
+	case config.StepJust:
+		return "Just targets"
+	case config.StepChangelog:
+		return "Generate changelog"
+	case config.StepCommitTag:
+		return "Commit & tag"
+	case config.StepPush:
+		return "Push to remote"
+	case config.StepWaitWorkflows:
+		return "Wait for workflows"
+	case config.StepGitHubRelease:
+		return "GitHub Release"
+	case config.StepPyPI:
+		return "PyPI"
+	case config.StepDockerHub:
+		return "Container images"
+	case config.StepNpm, config.StepCrates, config.StepMaven, config.StepGoProxy:
+		kind, _ := artifactKindFor(sc.Type)
+		return kind.title
+	case config.StepShell:
+		run := strings.TrimSpace(sc.Run)
+		if i := strings.IndexByte(run, '\n'); i >= 0 {
+			run = run[:i] + " ..."
+		}
+		return "Run: " + run
+	case config.StepNotify:
+		return "Notify Slack"
+	}
+	return sc.Type
+}
+
+// buildReleasePipeline resolves release.steps (or the default pipeline) into runnable steps.
+// Display names are made unique (e.g. "PyPI (2)") since the state file tracks steps by name.
+func buildReleasePipeline(cfg *config.Config) ([]releaseStep, error) {
+	var steps []releaseStep
+	seen := make(map[string]int)
+	for i, sc := range cfg.ReleaseSteps() {
+		sc.Type = strings.ToLower(strings.TrimSpace(sc.Type))
+		run, ok := releaseStepTypes[sc.Type]
+		if !ok {
+			return nil, fmt.Errorf("release.steps[%d]: unknown step type %q", i, sc.Type)
+		}
+		if sc.Type == config.StepShell && strings.TrimSpace(sc.Run) == "" {
+			return nil, fmt.Errorf("release.steps[%d]: shell step requires run", i)
+		}
+		name := sc.Name
+		if name == "" {
+			name = defaultReleaseStepName(sc)
+		}
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name = fmt.Sprintf("%s (%d)", name, n)
+		}
+		steps = append(steps, releaseStep{name: name, cfg: sc, run: run})
+	}
+	return steps, nil
+}
+
+// releaseStepPlanLines returns what a step would do, for the dry-run plan. Empty for steps that would be skipped.
+func releaseStepPlanLines(params *releaseParams, step releaseStep) []string {
+	cfg := params.cfg
+	sc := step.cfg
+	switch sc.Type {
+	case config.StepJust:
+		if targets := stepJustTargets(cfg, sc); len(targets) > 0 {
+			return []string{fmt.Sprintf("Just targets completed: %v", targets)}
+		}
+	case config.StepChangelog:
+		return []string{"Changelog written to " + params.outPathAbs}
+	case config.StepCommitTag:
+		return []string{"Committed and tagged " + params.nextTagForRef}
+	case config.StepPush:
+		return []string{
+			"Pushed " + params.branch + " to " + params.remote,
+			"Pushed tag " + params.nextTagForRef + " to " + params.remote,
+		}
+	case config.StepWaitWorkflows:
+		if reruns := stepWorkflowReruns(cfg, sc); reruns > 0 {
+			return []string{fmt.Sprintf("All release workflow(s) completed (failed jobs re-run up to %d time(s))", reruns)}
+		}
+		return []string{"All release workflow(s) completed"}
+	case config.StepGitHubRelease:
+		lines := []string{"Published GitHub Release " + params.nextTagForRef}
+		if assets := stepAssets(cfg, sc); len(assets) > 0 {
+			lines = append(lines, fmt.Sprintf("Uploaded release assets matching %v", assets))
+		}
+		return lines
+	case config.StepPyPI:
+		if pkg := stepPyPIPackage(cfg, sc); pkg.Name != "" {
+			where := pkg.Index
+			if index, err := resolvePyPIIndex(cfg, pkg.Index); err == nil {
+				where = index.Host()
+			}
+			pkgVersion := strings.TrimPrefix(params.versionTag, "v")
+			lines := []string{fmt.Sprintf("Package %s==%s is available on %s", pkg.Name, pkgVersion, where)}
+			if files := stepPyPIFiles(cfg, sc); len(files) > 0 {
+				lines = append(lines, fmt.Sprintf("Package %s==%s provides %s", pkg.Name, pkgVersion, strings.Join(files, ", ")))
+			}
+			return lines
+		}
+	case config.StepDockerHub:
+		var lines []string
+		verify := stepImageVerify(cfg, sc, params.versionTag)
+		for _, image := range stepDockerImages(cfg, sc) {
+			lines = append(lines, fmt.Sprintf("Image %s:%s is available", image, params.versionTag))
+			if len(verify.Platforms) > 0 {
+				lines = append(lines, fmt.Sprintf("Image %s:%s provides %s", image, params.versionTag, strings.Join(verify.Platforms, ", ")))
+			}
+			for label := range verify.Labels {
+				lines = append(lines, fmt.Sprintf("Image %s:%s has label %s=%s", image, params.versionTag, label, params.versionTag))
+			}
+		}
+		return lines
+	case config.StepNpm, config.StepCrates, config.StepMaven, config.StepGoProxy:
+		kind, _ := artifactKindFor(sc.Type)
+		packages, url := stepArtifactPackages(cfg, sc, kind)
+		var lines []string
+		for _, pkg := range packages {
+			ref := artifact.Ref(pkg, strings.TrimPrefix(params.versionTag, "v"))
+			lines = append(lines, fmt.Sprintf("%s is available on %s", ref, kind.newChecker(url).Registry()))
+		}
+		return lines
+	case config.StepShell:
+		return []string{"Ran " + strings.TrimSpace(sc.Run)}
+	case config.StepNotify:
+		return []string{"Sent Slack notification"}
+	}
+	return nil
+}
+
+func stepJustTargets(cfg *config.Config, sc config.ReleaseStepConfig) []string {
+	if len(sc.Targets) > 0 {
+		return sc.Targets
+	}
+	if cfg.Justfile != nil {
+		return cfg.Justfile.Targets
+	}
+	return nil
+}
+
+// stepPyPIPackage returns the package and index for a pypi step: release.pypi_package with the step's
+// package and index overriding its fields.
+func stepPyPIPackage(cfg *config.Config, sc config.ReleaseStepConfig) config.PyPIPackage {
+	var pkg config.PyPIPackage
+	if cfg.Release != nil {
+		pkg = cfg.Release.PyPIPackage
+	}
+	if sc.Package != "" {
+		pkg.Name = sc.Package
+	}
+	if sc.Index != "" {
+		pkg.Index = sc.Index
+	}
+	return pkg
+}
+
+func stepPyPIFiles(cfg *config.Config, sc config.ReleaseStepConfig) []string {
+	if len(sc.Files) > 0 {
+		return sc.Files
+	}
+	if cfg.Release != nil {
+		return cfg.Release.PyPIFiles
+	}
+	return nil
+}
+
+func stepDockerImages(cfg *config.Config, sc config.ReleaseStepConfig) []string {
+	if len(sc.Image) > 0 {
+		return sc.Image
+	}
+	if cfg.Release != nil {
+		return cfg.Release.DockerImage
+	}
+	return nil
+}
+
+func stepAssets(cfg *config.Config, sc config.ReleaseStepConfig) []string {
+	if len(sc.Assets) > 0 {
+		return sc.Assets
+	}
+	if cfg.Release != nil {
+		return cfg.Release.Assets
+	}
+	return nil
+}
+
+// stepImageVerify returns the platforms and version label the released images must have (step options,
+// else release.image_platforms and release.image_version_label); the label must match version.
+func stepImageVerify(cfg *config.Config, sc config.ReleaseStepConfig, version string) registry.VerifyOptions {
+	var opts registry.VerifyOptions
+	label := sc.VersionLabel
+	opts.Platforms = sc.Platforms
+	if cfg.Release != nil {
+		if len(opts.Platforms) == 0 {
+			opts.Platforms = cfg.Release.ImagePlatforms
+		}
+		if label == "" {
+			label = cfg.Release.ImageVersionLabel
+		}
+	}
+	if label != "" {
+		opts.Labels = map[string]string{label: version}
+	}
+	return opts
+}
+
+// stepTimeout returns the step's timeout option, or def when unset.
+func stepTimeout(sc config.ReleaseStepConfig, def time.Duration) time.Duration {
+	if sc.Timeout > 0 {
+		return sc.Timeout
+	}
+	return def
+}
+
+// releaseStepJust runs the configured just targets.
+func releaseStepJust(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	cfg := params.cfg
+	targets := stepJustTargets(cfg, sc)
+	if len(targets) == 0 {
+		return true, nil
+	}
+	workDir := params.repoAbs
+	if sc.WorkingDir != "" {
+		workDir = sc.WorkingDir
+	} else if cfg.Justfile != nil && cfg.Justfile.WorkingDir != "" {
+		workDir = cfg.Justfile.WorkingDir
+	}
+	result, err := just.Runner(workDir, targets)
+	if err != nil {
+		return false, fmt.Errorf("just: %w", err)
+	}
+	if !result.Success() {
+		return false, fmt.Errorf("just target(s) failed: %v", result.Failed)
+	}
+	logf("✓ Just targets completed: %v\n", targets)
+	return false, nil
+}
+
+// releaseStepChangelog generates the changelog section for the release tag. When params.reviewChangelog is
+// set, the section is reviewed (and possibly edited or regenerated) before the step completes. When the step
+// fails, the changelog is put back as it was.
+func releaseStepChangelog(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	prevData, err := os.ReadFile(params.outPathAbs)
+	existed := err == nil
+	resuming := false
+	if st := params.state; st != nil {
+		if st.Changelog == "" {
+			// Remember the previous contents so rollback can restore them.
+			st.Changelog, err = filepath.Rel(params.repoAbs, params.outPathAbs)
+			if err != nil {
+				st.Changelog = params.outPath
+			}
+			st.ChangelogExisted = existed
+			st.PrevChangelog = string(prevData)
+		} else {
+			// Resuming after a failed attempt, whose section may still be in the file: start from the
+			// contents recorded before the release.
+			prevData, existed, resuming = []byte(st.PrevChangelog), st.ChangelogExisted, true
+		}
+	}
+	// restore puts back the changelog as it was before the step.
+	restore := func() error {
+		if existed {
+			return os.WriteFile(params.outPathAbs, prevData, 0644)
+		}
+		if err := os.Remove(params.outPathAbs); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	generate := func(cfg *config.Config) (string, error) {
+		if err := generateChangelogSection(params.ctx, cfg, params.repoAbs, params.prev, params.branch, params.nextTagForRef, params.outPathAbs, 0, usePRs, useHistory, nil, nil, nil, nil); err != nil {
+			return "", fmt.Errorf("changelog: %w", err)
+		}
+		data, err := os.ReadFile(params.outPathAbs)
+		if err != nil {
+			return "", fmt.Errorf("changelog: %w", err)
+		}
+		return strings.TrimSpace(strings.TrimSuffix(string(data), string(prevData))) + "\n", nil
+	}
+	if resuming {
+		if err := restore(); err != nil {
+			return false, fmt.Errorf("restore changelog: %w", err)
+		}
+	}
+	if err := reviewChangelogSection(params, generate, restore, string(prevData)); err != nil {
+		if rerr := restore(); rerr != nil {
+			logf("warning: could not restore %s: %v\n", params.outPathAbs, rerr)
+		}
+		return false, err
+	}
+	logf("✓ Changelog written to %s\n", params.outPathAbs)
+	return false, nil
+}
+
+// reviewChangelogSection generates the section with generate and, when params.reviewChangelog is set, has it
+// reviewed and writes the reviewed section above prev. restore resets the file before regenerating.
+func reviewChangelogSection(params *releaseParams, generate func(cfg *config.Config) (string, error), restore func() error, prev string) error {
+	section, err := generate(params.cfg)
+	if err != nil || params.reviewChangelog == nil {
+		return err
+	}
+	regenerate := func(model string) (string, error) {
+		cfg, err := withLLMModel(params.cfg, model)
+		if err != nil {
+			return "", err
+		}
+		// Generate prepends to the file, so put back the previous contents first.
+		if err := restore(); err != nil {
+			return "", fmt.Errorf("changelog: %w", err)
+		}
+		return generate(cfg)
+	}
+	_, model, _ := resolveLLMConfig(params.cfg)
+	section, err = params.reviewChangelog(section, model, regenerate)
+	if err != nil {
+		return fmt.Errorf("changelog review: %w", err)
+	}
+	if err := os.WriteFile(params.outPathAbs, []byte(changelog.Prepend(section, prev)), 0644); err != nil {
+		return fmt.Errorf("write changelog: %w", err)
+	}
+	return nil
+}
+
+// releaseStepCommitTag commits the changelog and creates the annotated release tag. It is safe to re-run on
+// --resume: the commit is skipped when HEAD is already the recorded release commit, and the tag when it
+// already exists at HEAD.
+func releaseStepCommitTag(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	ctx := params.ctx
+	repoAbs := params.repoAbs
+	tag := params.nextTagForRef
+	head, _ := git.RevParse(ctx, repoAbs, "HEAD")
+	if st := params.state; st != nil && st.CommitSHA != "" && st.CommitSHA == head {
+		logf("✓ Changelog already committed (%s)\n", shortSHA(head))
+	} else {
+		changelogRel, err := filepath.Rel(repoAbs, params.outPathAbs)
+		if err != nil {
+			changelogRel = params.outPath
+		}
+		if err := git.Add(ctx, repoAbs, changelogRel); err != nil {
+			return false, err
+		}
+		if err := git.CreateCommit(ctx, repoAbs, "changelog: release "+tag); err != nil {
+			return false, err
+		}
+		if head, err = git.RevParse(ctx, repoAbs, "HEAD"); err == nil && params.state != nil {
+			params.state.CommitSHA = head
+			// Save now so a resume after a failed tag knows the commit was made.
+			if err := params.state.Save(params.statePath); err != nil {
+				logf("warning: %v\n", err)
+			}
+		}
+	}
+	if sha, err := git.RevParse(ctx, repoAbs, "refs/tags/"+tag+"^{commit}"); err == nil && sha == head {
+		logf("✓ Tag %s already exists at %s\n", tag, shortSHA(head))
+	} else if err := git.CreateTag(ctx, repoAbs, tag, "Release "+tag); err != nil {
+		return false, err
+	}
+	logf("✓ Committed and tagged %s\n", tag)
+	return false, nil
+}
+
+// releaseStepPush pushes the release branch and tag to the remote.
+func releaseStepPush(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	if err := git.Push(params.ctx, params.repoAbs, params.remote, "refs/heads/"+params.branch); err != nil {
+		return false, err
+	}
+	if err := git.Push(params.ctx, params.repoAbs, params.remote, "refs/tags/"+params.nextTagForRef); err != nil {
+		return false, err
+	}
+	logf("✓ Pushed %s to %s\n", params.branch, params.remote)
+	logf("✓ Pushed tag %s to %s\n", params.nextTagForRef, params.remote)
+	return false, nil
+}
+
+// releaseStepWaitWorkflows waits for the workflows triggered by the tag push to complete (the tag's pipelines
+// on GitLab).
+func releaseStepWaitWorkflows(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	ctx := params.ctx
+	repoAbs := params.repoAbs
+	sha, err := git.RevParse(ctx, repoAbs, params.nextTagForRef)
+	if err != nil {
+		return false, fmt.Errorf("resolve tag to SHA: %w", err)
+	}
+	if gl, ok, err := releaseGitLabClient(ctx, params.cfg, repoAbs, params.remote); err != nil {
+		return false, err
+	} else if ok {
+		if gl == nil {
+			logf("warning: no GITLAB_TOKEN; skipping pipeline wait\n")
+			return true, nil
+		}
+		return releaseStepWaitPipelines(params, sc, gl, sha, logf)
+	}
+	gh, err := releaseGitHubClient(ctx, params.cfg, repoAbs, params.remote)
+	if err != nil {
+		return false, err
+	}
+	if gh == nil {
+		logf("warning: no GitHub credentials (%s); skipping workflow wait\n", githubCredentialsHint)
+		return true, nil
+	}
+	triggers, _ := github.WorkflowsTriggeredByTag(repoAbs, params.nextTagForRef)
+	tagPushTriggers := github.TagPushWorkflows(triggers)
+	pollInterval := 15 * time.Second
+	reruns := stepWorkflowReruns(params.cfg, sc)
+	var last string
+	wait := workflowWait{
+		sha:      sha,
+		triggers: tagPushTriggers,
+		timeout:  stepTimeout(sc, params.releaseWaitTo),
+		interval: pollInterval,
+		progress: func(runs []*github.WorkflowRun, jobs map[int64][]github.JobProgress) {
+			lines := activeJobLines(runs, jobs)
+			if len(runs) == 0 {
+				lines = []string{"Waiting for release workflows to start..."}
+			} else if len(lines) == 0 {
+				lines = []string{"Waiting for workflows..."}
+			}
+			text := strings.Join(lines, "\n")
+			if params.stepDetail != nil {
+				params.stepDetail(text)
+				return
+			}
+			if text == last {
+				return
+			}
+			last = text
+			logf("%s (next check in %s)\n", text, pollInterval)
+		},
+	}
+	// Each attempt (the first wait and each re-run) gets the full timeout.
+	for attempt := 0; ; attempt++ {
+		runs, err := waitForWorkflows(ctx, gh, wait)
+		if errors.Is(err, errWorkflowTimeout) {
+			return false, fmt.Errorf("timeout waiting for release workflows")
+		}
+		if err != nil {
+			return false, fmt.Errorf("list workflow runs: %w", err)
+		}
+		if !github.AnyRunFailed(runs) {
+			logf("✓ All release workflow(s) completed\n")
+			return false, nil
+		}
+		report := failedJobsReport(ctx, gh, runs)
+		if attempt >= reruns {
+			if report != "" {
+				return false, fmt.Errorf("one or more release workflows failed\n%s", report)
+			}
+			return false, fmt.Errorf("one or more release workflows failed")
+		}
+		if report != "" {
+			logf("%s\n", report)
+		}
+		failed := github.FailedRuns(runs)
+		logf("Re-running failed jobs of %d workflow run(s) (re-run %d of %d)\n", len(failed), attempt+1, reruns)
+		if params.stepDetail != nil {
+			params.stepDetail(fmt.Sprintf("Re-running %d failed workflow run(s) (re-run %d of %d)", len(failed), attempt+1, reruns))
+		}
+		wait.attempts, err = rerunFailedRuns(ctx, gh, runs, false)
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+// stepWorkflowReruns returns how many times a wait_workflows step re-runs failed workflows: the step's
+// reruns option, else release.workflow_reruns.
+func stepWorkflowReruns(cfg *config.Config, sc config.ReleaseStepConfig) int {
+	if sc.Reruns > 0 {
+		return sc.Reruns
+	}
+	if cfg.Release != nil {
+		return cfg.Release.WorkflowReruns
+	}
+	return 0
+}
+
+// releaseGitHubClient returns a GitHub client for the repo and credentials resolved by resolveGitHub.
+// Returns nil and no error when no credentials are found.
+func releaseGitHubClient(ctx context.Context, cfg *config.Config, repoAbs, remote string) (*github.Client, error) {
+	access, err := resolveGitHub(ctx, cfg, repoAbs, remote)
+	if err != nil || access.ts == nil {
+		return nil, err
+	}
+	return access.client(ctx, cfg)
+}
+
+// releaseStepGitHubRelease creates (or updates) the GitHub Release for the tag with the changelog section
+// as its notes, and uploads the files matching the step's assets (or release.assets). On GitLab it publishes
+// a GitLab Release instead.
+func releaseStepGitHubRelease(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	if gl, ok, err := releaseGitLabClient(params.ctx, params.cfg, params.repoAbs, params.remote); err != nil {
+		return false, err
+	} else if ok {
+		if gl == nil {
+			logf("warning: no GITLAB_TOKEN; skipping GitLab Release\n")
+			return true, nil
+		}
+		_, created, err := publishGitLabRelease(params.ctx, gl, params.repoAbs, params.nextTagForRef, params.outPathAbs, stepAssets(params.cfg, sc), logf)
+		if created && params.state != nil {
+			params.state.GitLabReleaseTag = params.nextTagForRef
+		}
+		if err != nil {
+			return false, err
+		}
+		logf("✓ Published GitLab Release %s\n", params.nextTagForRef)
+		return false, nil
+	}
+	gh, err := releaseGitHubClient(params.ctx, params.cfg, params.repoAbs, params.remote)
+	if err != nil {
+		return false, err
+	}
+	if gh == nil {
+		logf("warning: no GitHub credentials (%s); skipping GitHub Release\n", githubCredentialsHint)
+		return true, nil
+	}
+	rel, created, err := publishGitHubRelease(params.ctx, gh, params.cfg, params.repoAbs, params.nextTagForRef, params.outPathAbs, stepAssets(params.cfg, sc), logf)
+	if created && params.state != nil {
+		params.state.GitHubReleaseID = rel.GetID()
+	}
+	if err != nil {
+		return false, err
+	}
+	logf("✓ Published GitHub Release %s\n", params.nextTagForRef)
+	return false, nil
+}
+
+// publishGitHubRelease creates or updates the GitHub Release for tag. The notes are tag's section of the
+// changelog at changelogPath (GitHub generates notes when there is none); the release is marked as a
+// prerelease when the tag has a prerelease kind (rc, alpha, ...). Files matching the asset globs (relative
+// to repoAbs) are then uploaded. rel and created are set once the release exists, even if an upload fails.
+func publishGitHubRelease(ctx context.Context, gh *github.Client, cfg *config.Config, repoAbs, tag, changelogPath string, assetGlobs []string, logf func(format string, args ...interface{})) (rel *github.Release, created bool, err error) {
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return nil, false, err
+	}
+	files, err := expandAssetGlobs(repoAbs, assetGlobs)
+	if err != nil {
+		return nil, false, err
+	}
+	notes := ""
+	if data, err := os.ReadFile(changelogPath); err == nil {
+		notes = changelog.Section(string(data), tag)
+	}
+	v := semver.ParseTagScheme(strings.TrimPrefix(tag, cfg.TagPrefix()), scheme)
+	opts := github.ReleaseOptions{Tag: tag, Body: notes, Prerelease: v != nil && v.PreKind != ""}
+	rel, created, err = gh.CreateOrUpdateRelease(ctx, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, f := range files {
+		if err := gh.UploadReleaseAsset(ctx, rel.GetID(), f); err != nil {
+			return rel, created, err
+		}
+		logf("✓ Uploaded %s\n", filepath.Base(f))
+	}
+	return rel, created, nil
+}
+
+// expandAssetGlobs returns the files matching globs (relative to repoAbs). A glob matching nothing is an error
+// so a missing build artifact fails the release instead of silently publishing without it.
+func expandAssetGlobs(repoAbs string, globs []string) ([]string, error) {
+	var files []string
+	for _, g := range globs {
+		pattern := g
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(repoAbs, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("asset glob %q: %w", g, err)
+		}
+		n := 0
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err == nil && !fi.IsDir() {
+				files = append(files, m)
+				n++
+			}
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("asset glob %q matched no files", g)
+		}
+	}
+	return files, nil
+}
+
+// releaseStepPyPI waits for the package (step package or release.pypi_package) at the release version on its
+// index (PyPI unless the step or release.pypi_package names another), and until the expected distribution
+// files (step files or release.pypi_files) are all uploaded.
+func releaseStepPyPI(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	pkg := stepPyPIPackage(params.cfg, sc)
+	if pkg.Name == "" {
+		return true, nil
+	}
+	index, err := resolvePyPIIndex(params.cfg, pkg.Index)
+	if err != nil {
+		return false, err
+	}
+	pkgVersion := strings.TrimPrefix(params.versionTag, "v")
+	opts := pypi.WaitOptions{
+		Timeout:  stepTimeout(sc, params.releasePyPITo),
+		Interval: 5 * time.Second,
+		Verify:   pypi.VerifyOptions{Files: stepPyPIFiles(params.cfg, sc)},
+		Progress: waitProgress(params.stepDetail, logf),
+	}
+	if err := pypi.NewClient(index).Wait(params.ctx, pkg.Name, pkgVersion, opts); err != nil {
+		return false, fmt.Errorf("pypi wait: %w", err)
+	}
+	logf("✓ Package %s==%s is available on %s\n", pkg.Name, pkgVersion, index.Host())
+	return false, nil
+}
+
+// releaseStepDockerHub waits for each image (step image or release.docker_image) tagged with the release tag
+// in its registry (Docker Hub, GHCR, or any OCI registry), and until it provides the expected platforms and
+// version label when configured. The timeout applies to each image in turn.
+func releaseStepDockerHub(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	images := stepDockerImages(params.cfg, sc)
+	if len(images) == 0 {
+		return true, nil
+	}
+	client := registry.NewClient()
+	opts := registry.WaitOptions{
+		Timeout:  stepTimeout(sc, params.releaseDockerTo),
+		Interval: 5 * time.Second,
+		Verify:   stepImageVerify(params.cfg, sc, params.versionTag),
+		Progress: waitProgress(params.stepDetail, logf),
+	}
+	for _, image := range images {
+		imageRef := image + ":" + params.versionTag
+		if err := client.Wait(params.ctx, imageRef, opts); err != nil {
+			return false, fmt.Errorf("image wait: %w", err)
+		}
+		logf("✓ Image %s is available\n", imageRef)
+		if len(opts.Verify.Platforms) > 0 {
+			logf("✓ Image %s provides %s\n", imageRef, strings.Join(opts.Verify.Platforms, ", "))
+		}
+	}
+	return false, nil
+}
+
+// waitProgress returns a progress callback for a registry wait: with detail (the release TUI) every poll
+// updates the running step's detail line; otherwise logf prints retried errors and changes in what is missing.
+func waitProgress(detail func(string), logf func(format string, args ...interface{})) func(artifact.Progress) {
+	var last string
+	return func(p artifact.Progress) {
+		if detail != nil {
+			detail(p.String())
+			return
+		}
+		if p.Err == nil && p.Reason == last {
+			return
+		}
+		last = p.Reason
+		logf("… %s\n", p)
+	}
+}
+
+// releaseStepArtifact waits for each package configured for the step's registry (npm, crates, maven or
+// goproxy) at the release version. The timeout applies to each package in turn.
+func releaseStepArtifact(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	kind, ok := artifactKindFor(sc.Type)
+	if !ok {
+		return false, fmt.Errorf("unknown artifact registry %q", sc.Type)
+	}
+	packages, url := stepArtifactPackages(params.cfg, sc, kind)
+	if len(packages) == 0 {
+		return true, nil
+	}
+	checker := kind.newChecker(url)
+	version := strings.TrimPrefix(params.versionTag, "v")
+	opts := artifact.WaitOptions{
+		Timeout:  stepTimeout(sc, params.releaseArtifactTo),
+		Interval: 5 * time.Second,
+		Progress: waitProgress(params.stepDetail, logf),
+	}
+	for _, pkg := range packages {
+		if err := artifact.Wait(params.ctx, checker, pkg, version, opts); err != nil {
+			return false, fmt.Errorf("%s wait: %w", kind.step, err)
+		}
+		logf("✓ %s is available on %s\n", artifact.Ref(pkg, version), checker.Registry())
+	}
+	return false, nil
+}
+
+// releaseStepShell runs the step's command with sh -c in the repo (or working_dir).
+// Output goes to stderr in plain mode and is discarded under the TUI (it is included in the error on failure).
+func releaseStepShell(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	ctx := params.ctx
+	if sc.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sc.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", sc.Run)
+	cmd.Dir = params.repoAbs
+	if sc.WorkingDir != "" {
+		cmd.Dir = sc.WorkingDir
+	}
+	cmd.Env = append(os.Environ(),
+		"RELEASEBOT_TAG="+params.nextTagForRef,
+		"RELEASEBOT_PREV_TAG="+params.prev,
+		"RELEASEBOT_BRANCH="+params.branch,
+		"RELEASEBOT_REMOTE="+params.remote,
+	)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	logf("%s", out.String())
+	if err != nil {
+		return false, fmt.Errorf("shell %q: %w (%s)", strings.TrimSpace(sc.Run), err, lastLines(out.String(), 5))
+	}
+	logf("✓ Ran %s\n", strings.TrimSpace(sc.Run))
+	return false, nil
+}
+
+// releaseStepNotify posts the step's message to Slack (slack.webhook_url or SLACK_WEBHOOK_URL).
+func releaseStepNotify(params *releaseParams, sc config.ReleaseStepConfig, logf func(format string, args ...interface{})) (bool, error) {
+	webhookURL := ""
+	if params.cfg.Slack != nil {
+		webhookURL = params.cfg.Slack.WebhookURL
+	}
+	if webhookURL == "" && os.Getenv("SLACK_WEBHOOK_URL") == "" {
+		logf("warning: no Slack webhook configured; skipping notification\n")
+		return true, nil
+	}
+	msg := sc.Message
+	if msg == "" {
+		msg = "Released {{.Tag}}"
+	}
+	tmpl, err := template.New("notify").Parse(msg)
+	if err != nil {
+		return false, fmt.Errorf("notify message template: %w", err)
+	}
+	var b strings.Builder
+	data := struct{ Tag, PrevTag, Branch, Remote string }{params.nextTagForRef, params.prev, params.branch, params.remote}
+	if err := tmpl.Execute(&b, data); err != nil {
+		return false, fmt.Errorf("notify message template: %w", err)
+	}
+	if err := slack.Notify(webhookURL, b.String()); err != nil {
+		return false, fmt.Errorf("slack: %w", err)
+	}
+	logf("✓ Sent Slack notification\n")
+	return false, nil
+}
+
+// lastLines returns the last n non-empty lines of s joined with "; " (for compact error messages).
+func lastLines(s string, n int) string {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, strings.TrimSpace(l))
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "; ")
+}
@@ -1,17 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnewart/releasebot/internal/changelog"
 	"github.com/johnewart/releasebot/internal/sound"
 )
 
-const numReleaseSteps = 7
-
 // stepResultMsg is sent after each release step completes (from doReleaseSteps reporter).
 type stepResultMsg struct {
 	Step    int
@@ -19,11 +20,59 @@ type stepResultMsg struct {
 	Skipped bool
 }
 
+// stepDetailMsg is sent while a step runs to show progress under it (e.g. a registry wait's last poll).
+type stepDetailMsg struct {
+	Detail string
+}
+
 // releaseDoneMsg is sent when doReleaseSteps returns (success or final error).
 type releaseDoneMsg struct {
 	Err error
 }
 
+// rollbackActionMsg is sent after each undo action when --rollback-on-failure rolls back a failed release.
+type rollbackActionMsg struct {
+	Desc string
+	Err  error
+}
+
+// changelogReviewMsg is sent by the changelog step to review the generated section; the reviewed section (or
+// an error to stop the release) is sent back on Reply.
+type changelogReviewMsg struct {
+	Section    string
+	Model      string
+	Regenerate func(model string) (string, error)
+	Reply      chan changelogReviewResult
+}
+
+type changelogReviewResult struct {
+	Section string
+	Err     error
+}
+
+// changelogEditedMsg is sent when $EDITOR exits after editing the section under review (saved at Path).
+type changelogEditedMsg struct {
+	Path string
+	Err  error
+}
+
+// changelogRegeneratedMsg is sent when regenerating the section under review with Model finishes.
+type changelogRegeneratedMsg struct {
+	Section string
+	Model   string
+	Err     error
+}
+
+// changelogReview is the state of the changelog review stage shown before "Commit & tag".
+type changelogReview struct {
+	changelogReviewMsg
+	cursor       int  // selected entry (index into changelog.Entries)
+	editingModel bool // typing the model to regenerate with
+	modelInput   string
+	regenerating bool
+	note         string // outcome of the last action (e.g. an editor error)
+}
+
 // dryRunStatusMsg is sent during dry-run gather to show progress (e.g. "Found 12 commits").
 type dryRunStatusMsg struct {
 	Line string
@@ -43,9 +92,10 @@ type dryRunPlanMsg struct {
 
 type releaseTUI struct {
 	params              *releaseParams
-	ch                  chan interface{}        // stepResultMsg, releaseDoneMsg, or dryRunPlanMsg
-	status              [numReleaseSteps]string // "pending" | "running" | "done" | "skipped" | "error"
+	ch                  chan interface{} // stepResultMsg, releaseDoneMsg, or dryRunPlanMsg
+	status              []string         // per pipeline step: "pending" | "running" | "done" | "skipped" | "error"
 	current             int
+	detail              string // progress line under the running step
 	spinner             spinner.Model
 	done                bool
 	finalErr            error
@@ -55,6 +105,8 @@ type releaseTUI struct {
 	dryRunProgressCur   int      // for progress bar (fetching PRs)
 	dryRunProgressTotal int
 	dryRunProgressBar   progress.Model
+	rollbackLines       []string         // undo actions run by --rollback-on-failure
+	review              *changelogReview // set while the changelog section is being reviewed
 }
 
 func newReleaseTUI(params *releaseParams) *releaseTUI {
@@ -67,14 +119,14 @@ func newReleaseTUI(params *releaseParams) *releaseTUI {
 	return &releaseTUI{
 		params:            params,
 		ch:                make(chan interface{}, 1),
-		status:            [numReleaseSteps]string{},
+		status:            make([]string, len(params.steps)),
 		spinner:           s,
 		dryRunProgressBar: pg,
 	}
 }
 
 func (m *releaseTUI) Init() tea.Cmd {
-	for i := 0; i < numReleaseSteps; i++ {
+	for i := 0; i < len(m.params.steps); i++ {
 		m.status[i] = "pending"
 	}
 	m.dryRunMode = m.params.dryRun
@@ -85,6 +137,15 @@ func (m *releaseTUI) Init() tea.Cmd {
 	} else {
 		m.status[0] = "running"
 		m.current = 0
+		m.params.stepDetail = func(detail string) {
+			m.ch <- stepDetailMsg{Detail: detail}
+		}
+		m.params.reviewChangelog = func(section, model string, regenerate func(model string) (string, error)) (string, error) {
+			reply := make(chan changelogReviewResult)
+			m.ch <- changelogReviewMsg{Section: section, Model: model, Regenerate: regenerate, Reply: reply}
+			res := <-reply
+			return res.Section, res.Err
+		}
 		go func() {
 			report := func(step int, err error, skipped bool) {
 				if skipped {
@@ -94,6 +155,9 @@ func (m *releaseTUI) Init() tea.Cmd {
 				m.ch <- stepResultMsg{Step: step, Err: err}
 			}
 			err := doReleaseSteps(m.params, report, nil)
+			err = withRollbackOnFailure(m.params, err, func(desc string, err error) {
+				m.ch <- rollbackActionMsg{Desc: desc, Err: err}
+			})
 			m.ch <- releaseDoneMsg{Err: err}
 		}()
 	}
@@ -121,25 +185,18 @@ func (m *releaseTUI) runDryRunGather() {
 	}
 	// ✅ = actually ran during dry-run; ⏭️ = would run / skipped
 	lines = append(lines, "✅ Previous tag "+m.params.prev+" validated")
-	if m.params.cfg.Justfile != nil && len(m.params.cfg.Justfile.Targets) > 0 {
-		lines = append(lines, fmt.Sprintf("✅ Just targets completed: %v", m.params.cfg.Justfile.Targets))
+	for _, line := range githubDryRunReport(ctx, m.params.cfg, m.params.repoAbs, m.params.remote) {
+		lines = append(lines, "✅ "+line)
 	}
 	if len(src.PRs) > 0 {
 		lines = append(lines, fmt.Sprintf("✅ Found %d merged PR(s) between %s and %s", len(src.PRs), m.params.prev, m.params.branch))
 	} else {
 		lines = append(lines, fmt.Sprintf("✅ Found %d commit(s) between %s and %s", len(src.Commits), m.params.prev, m.params.branch))
 	}
-	lines = append(lines, "⏭️ Changelog written to "+m.params.outPathAbs)
-	lines = append(lines, "⏭️ Committed and tagged "+m.params.nextTagForRef)
-	lines = append(lines, "⏭️ Pushed "+m.params.branch+" to "+m.params.remote)
-	lines = append(lines, "⏭️ Pushed tag "+m.params.nextTagForRef+" to "+m.params.remote)
-	lines = append(lines, "⏭️ All release workflow(s) completed")
-	if m.params.cfg.Release != nil && m.params.cfg.Release.PyPIPackage != "" {
-		pkgVersion := strings.TrimPrefix(m.params.nextTagForRef, "v")
-		lines = append(lines, fmt.Sprintf("⏭️ Package %s==%s is available on PyPI", m.params.cfg.Release.PyPIPackage, pkgVersion))
-	}
-	if m.params.cfg.Release != nil && m.params.cfg.Release.DockerImage != "" {
-		lines = append(lines, fmt.Sprintf("⏭️ Image %s:%s is available on Docker Hub", m.params.cfg.Release.DockerImage, m.params.nextTagForRef))
+	for _, step := range m.params.steps {
+		for _, line := range releaseStepPlanLines(m.params, step) {
+			lines = append(lines, "⏭️ "+line)
+		}
 	}
 	lines = append(lines, "✅ Release "+m.params.nextTagForRef+" complete (dry-run)")
 	m.ch <- dryRunPlanMsg{Lines: lines}
@@ -154,14 +211,62 @@ func (m *releaseTUI) waitForMsg() tea.Cmd {
 func (m *releaseTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.Type == tea.KeyCtrlC || msg.String() == "q" {
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.review != nil {
+			return m.updateReview(msg)
+		}
+		if msg.String() == "q" {
 			return m, tea.Quit
 		}
 		if m.done {
 			return m, tea.Quit
 		}
 		return m, nil
+	case stepDetailMsg:
+		m.detail = msg.Detail
+		return m, tea.Batch(m.spinner.Tick, m.waitForMsg())
+	case changelogReviewMsg:
+		// The step waits for the reply, so no other messages arrive until the review is done.
+		m.review = &changelogReview{changelogReviewMsg: msg}
+		m.detail = "Review the changelog section below"
+		return m, nil
+	case changelogEditedMsg:
+		if m.review == nil {
+			return m, nil
+		}
+		defer os.Remove(msg.Path)
+		if msg.Err != nil {
+			m.review.note = "✗ editor: " + msg.Err.Error()
+			return m, nil
+		}
+		section, err := readEditedSection(msg.Path)
+		if errors.Is(err, errEmptyChangelog) {
+			m.review.note = "Section left empty in the editor; kept the previous one"
+			return m, nil
+		} else if err != nil {
+			m.review.note = "✗ " + err.Error()
+			return m, nil
+		}
+		m.review.setSection(section)
+		m.review.note = "✓ Edited in $EDITOR"
+		return m, nil
+	case changelogRegeneratedMsg:
+		if m.review == nil {
+			return m, nil
+		}
+		m.review.regenerating = false
+		if msg.Err != nil {
+			m.review.note = "✗ regenerate: " + msg.Err.Error()
+			return m, nil
+		}
+		m.review.Model = msg.Model
+		m.review.setSection(msg.Section)
+		m.review.note = "✓ Regenerated with " + msg.Model
+		return m, nil
 	case stepResultMsg:
+		m.detail = ""
 		if msg.Skipped {
 			m.status[msg.Step] = "skipped"
 		} else if msg.Err != nil {
@@ -172,15 +277,22 @@ func (m *releaseTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Next step running
 		next := msg.Step + 1
-		if next < numReleaseSteps && m.status[next] == "pending" {
+		if next < len(m.params.steps) && m.status[next] == "pending" {
 			m.status[next] = "running"
 			m.current = next
 		}
 		return m, tea.Batch(m.spinner.Tick, m.waitForMsg())
+	case rollbackActionMsg:
+		if msg.Err != nil {
+			m.rollbackLines = append(m.rollbackLines, "✗ "+msg.Desc+": "+msg.Err.Error())
+		} else {
+			m.rollbackLines = append(m.rollbackLines, "↩ "+msg.Desc)
+		}
+		return m, tea.Batch(m.spinner.Tick, m.waitForMsg())
 	case releaseDoneMsg:
 		m.done = true
 		m.finalErr = msg.Err
-		for i := 0; i < numReleaseSteps; i++ {
+		for i := 0; i < len(m.params.steps); i++ {
 			if m.status[i] == "running" {
 				if msg.Err != nil {
 					m.status[i] = "error"
@@ -221,6 +333,128 @@ func (m *releaseTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// updateReview handles keys during the changelog review stage.
+func (m *releaseTUI) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := m.review
+	if r.regenerating {
+		return m, nil
+	}
+	if r.editingModel {
+		switch msg.Type {
+		case tea.KeyEnter:
+			model := strings.TrimSpace(r.modelInput)
+			if model == "" {
+				return m, nil
+			}
+			r.editingModel = false
+			r.regenerating = true
+			r.note = ""
+			regenerate := r.Regenerate
+			return m, func() tea.Msg {
+				section, err := regenerate(model)
+				return changelogRegeneratedMsg{Section: section, Model: model, Err: err}
+			}
+		case tea.KeyEsc:
+			r.editingModel = false
+		case tea.KeyBackspace:
+			if n := len([]rune(r.modelInput)); n > 0 {
+				r.modelInput = string([]rune(r.modelInput)[:n-1])
+			}
+		case tea.KeyRunes:
+			r.modelInput += string(msg.Runes)
+		}
+		return m, nil
+	}
+	entries := changelog.Entries(r.Section)
+	switch msg.String() {
+	case "up", "k":
+		if r.cursor > 0 {
+			r.cursor--
+		}
+	case "down", "j":
+		if r.cursor < len(entries)-1 {
+			r.cursor++
+		}
+	case "d", "x", "delete":
+		if r.cursor < len(entries) {
+			r.setSection(changelog.DropEntry(r.Section, entries[r.cursor]))
+			r.note = "✓ Dropped entry"
+		}
+	case "e":
+		f, err := os.CreateTemp("", "releasebot-changelog-*.md")
+		if err == nil {
+			_, err = f.WriteString(r.Section)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			r.note = "✗ " + err.Error()
+			return m, nil
+		}
+		path := f.Name()
+		return m, tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+			return changelogEditedMsg{Path: path, Err: err}
+		})
+	case "r":
+		r.editingModel = true
+		r.modelInput = r.Model
+	case "enter", "a":
+		r.Reply <- changelogReviewResult{Section: r.Section}
+		m.review = nil
+		m.detail = ""
+		return m, tea.Batch(m.spinner.Tick, m.waitForMsg())
+	case "q":
+		r.Reply <- changelogReviewResult{Err: errors.New("changelog rejected")}
+		m.review = nil
+		m.detail = ""
+		return m, tea.Batch(m.spinner.Tick, m.waitForMsg())
+	}
+	return m, nil
+}
+
+// setSection replaces the section under review, keeping the cursor on an entry.
+func (r *changelogReview) setSection(section string) {
+	r.Section = section
+	if n := len(changelog.Entries(section)); r.cursor >= n {
+		r.cursor = max(n-1, 0)
+	}
+}
+
+// view renders the section under review with the selected entry marked, and the keys.
+func (r *changelogReview) view(spin string) string {
+	s := "\n  Review the changelog section"
+	if r.Model != "" {
+		s += " (written by " + r.Model + ")"
+	}
+	s += ":\n\n"
+	lines := strings.Split(strings.TrimRight(r.Section, "\n"), "\n")
+	selected := changelog.Entry{}
+	if entries := changelog.Entries(r.Section); r.cursor < len(entries) {
+		selected = entries[r.cursor]
+	}
+	for i, line := range lines {
+		marker := "    "
+		if i == selected.Start && selected.End > selected.Start {
+			marker = "  ▸ "
+		}
+		s += marker + line + "\n"
+	}
+	s += "\n"
+	switch {
+	case r.regenerating:
+		s += "  " + spin + " Regenerating the section...\n"
+	case r.editingModel:
+		s += "  Regenerate with model: " + r.modelInput + "█  (enter to regenerate, esc to cancel)\n"
+	default:
+		if r.note != "" {
+			s += "  " + r.note + "\n"
+		}
+		s += "  ↑/↓ select · d drop entry · e edit in $EDITOR · r regenerate with another model · enter accept · q reject\n"
+	}
+	return s
+}
+
 func (m *releaseTUI) View() string {
 	if m.dryRunMode {
 		title := " releasebot  release plan (dry-run) "
@@ -262,9 +496,9 @@ func (m *releaseTUI) View() string {
 	title := fmt.Sprintf(" releasebot  releasing %s ", m.params.nextTagForRef)
 	s := "\n  " + title + "\n\n"
 
-	for i := 0; i < numReleaseSteps; i++ {
+	for i := 0; i < len(m.params.steps); i++ {
 		prefix := "  "
-		if i < numReleaseSteps-1 {
+		if i < len(m.params.steps)-1 {
 			prefix = "├── "
 		} else {
 			prefix = "└── "
@@ -282,12 +516,39 @@ func (m *releaseTUI) View() string {
 		default:
 			icon = "○"
 		}
-		s += fmt.Sprintf("%s%s  %s\n", prefix, icon, releaseStepNames[i])
+		s += fmt.Sprintf("%s%s  %s\n", prefix, icon, m.params.steps[i].name)
+		if m.status[i] == "running" && m.detail != "" {
+			indent := "│      "
+			if i == len(m.params.steps)-1 {
+				indent = "       "
+			}
+			// Multi-line details (e.g. one line per running job) are listed under a single arrow.
+			for j, line := range strings.Split(m.detail, "\n") {
+				arrow := "↳ "
+				if j > 0 {
+					arrow = "  "
+				}
+				s += indent + arrow + line + "\n"
+			}
+		}
+	}
+	if m.review != nil {
+		s += m.review.view(m.spinner.View())
+	}
+	if len(m.rollbackLines) > 0 {
+		s += "\n  Rolling back:\n"
+		for i, line := range m.rollbackLines {
+			prefix := "├── "
+			if i == len(m.rollbackLines)-1 {
+				prefix = "└── "
+			}
+			s += "  " + prefix + line + "\n"
+		}
 	}
 
 	s += "\n"
 	if m.done && m.finalErr != nil {
-		s += "  " + m.finalErr.Error() + "\n"
+		s += "  " + strings.ReplaceAll(m.finalErr.Error(), "\n", "\n  ") + "\n"
 	} else if m.done {
 		s += "  ✅ Release " + m.params.nextTagForRef + " complete\n"
 	}
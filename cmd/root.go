@@ -17,6 +17,7 @@ var (
 	prLimit    int
 	useHistory bool
 	usePRs     bool
+	component  string
 )
 
 var rootCmd = &cobra.Command{
@@ -37,7 +38,7 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "report what would be done without performing actions")
 	rootCmd.PersistentFlags().BoolVar(&noTUI, "no-tui", false, "disable TUI and use plain stderr output (default: TUI when stdout is a terminal)")
 	rootCmd.PersistentFlags().BoolVar(&useHistory, "use-history", false, "use git commit history for changelog (overrides config)")
-	rootCmd.PersistentFlags().BoolVar(&usePRs, "use-prs", false, "use merged GitHub PRs for changelog (overrides config; requires github.enabled)")
+	rootCmd.PersistentFlags().BoolVar(&usePRs, "use-prs", false, "use merged GitHub PRs or GitLab merge requests for changelog (overrides config; requires github.enabled or gitlab.enabled)")
 }
 
 func Execute() {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,11 +11,13 @@ import (
 	"github.com/johnewart/releasebot/internal/cache"
 	"github.com/johnewart/releasebot/internal/changelog"
 	"github.com/johnewart/releasebot/internal/config"
+	"github.com/johnewart/releasebot/internal/forge"
 	"github.com/johnewart/releasebot/internal/git"
 	"github.com/johnewart/releasebot/internal/github"
 	"github.com/johnewart/releasebot/internal/just"
 	"github.com/johnewart/releasebot/internal/semver"
 	"github.com/johnewart/releasebot/internal/slack"
+	"github.com/johnewart/releasebot/internal/workpool"
 	"github.com/spf13/cobra"
 )
 
@@ -50,6 +53,10 @@ func runRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	cfg.Resolve(repoAbs)
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return err
+	}
 
 	// Resolve previous tag (CLI overrides config, then latest stable tag)
 	prev := prevTag
@@ -61,7 +68,7 @@ func runRun(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		prev = semver.LatestStableTag(tags)
+		prev = semver.LatestStableTagScheme(tags, scheme)
 		if prev == "" {
 			return fmt.Errorf("could not determine previous release tag: use --prev-tag, set previous_release_tag in config, or ensure repo has semver tags (e.g. v1.0.0)")
 		}
@@ -260,20 +267,12 @@ func generateChangelogSection(ctx context.Context, cfg *config.Config, repoAbs,
 	if err != nil {
 		return err
 	}
-	var owner, repo string
-	useGitHub := usePRs && cfg.GitHub != nil && cfg.GitHub.Enabled
-	if useGitHub {
-		owner = cfg.GitHub.Owner
-		repo = cfg.GitHub.Repo
-		if owner == "" || repo == "" {
-			remote, err := git.RemoteOriginURL(ctx, repoAbs)
-			if err != nil {
-				return fmt.Errorf("github not configured and could not get remote: %w", err)
-			}
-			owner, repo, err = git.ParseGitHubOwnerRepo(remote)
-			if err != nil {
-				return err
-			}
+	var fr forgeRepo
+	useForge := usePRs && cfg.PRsEnabled()
+	if useForge {
+		fr, err = resolveForgeRepo(ctx, cfg, repoAbs, defaultRemote(cfg))
+		if err != nil {
+			return err
 		}
 	}
 	src, err := gatherChangelogSource(ctx, cfg, repoAbs, prev, headRef, prLimit, usePRs, useHistory, report, reportProgress)
@@ -283,6 +282,7 @@ func generateChangelogSection(ctx context.Context, cfg *config.Config, repoAbs,
 	provider, model, baseURL := resolveLLMConfig(cfg)
 	useLLM := provider != ""
 	summarizePerPR, includeDiff, cacheLLMSummaries := resolvePerPRConfig(cfg)
+	concurrency, rpm := resolveLLMConcurrency(cfg)
 	opts := changelog.GenerateOptions{
 		Version:            version,
 		Format:             format,
@@ -295,14 +295,26 @@ func generateChangelogSection(ctx context.Context, cfg *config.Config, repoAbs,
 		SummarizePerPR:     summarizePerPR,
 		IncludeDiff:        includeDiff,
 		CacheLLMSummaries:  cacheLLMSummaries,
+		RenderTemplate:     resolveRenderTemplate(cfg),
+		Concurrency:        concurrency,
+		RequestsPerMinute:  rpm,
 		LLMSummaryCacheDir: filepath.Join(repoAbs, cache.DefaultDir, "llm_pr"),
 	}
-	if useGitHub {
-		opts.Owner = owner
-		opts.Repo = repo
-		opts.RepoURL = fmt.Sprintf("https://github.com/%s/%s", owner, repo)
+	if !useForge {
+		// Git history: link PR references in commit subjects when origin is on GitHub or GitLab.
+		fr, _ = resolveForgeRepo(ctx, cfg, repoAbs, defaultRemote(cfg))
+	}
+	if fr.webURL != "" {
+		opts.RepoURL = fr.webURL
+		opts.PRURLFormat = forge.PRURLFormat(fr.kind, fr.webURL)
+	}
+	if useForge {
+		opts.Owner = fr.owner
+		opts.Repo = fr.repo
 	}
-	if useLLM || summarizePerPR {
+	// Git history is grouped by Conventional Commit type through the template unless changelog.format is set.
+	groupHistory := len(src.PRs) == 0 && !cfg.HasChangelogFormat()
+	if useLLM || summarizePerPR || groupHistory {
 		tmpl, err := cfg.ChangelogTemplate(repoAbs)
 		if err != nil {
 			return fmt.Errorf("changelog template: %w", err)
@@ -311,25 +323,29 @@ func generateChangelogSection(ctx context.Context, cfg *config.Config, repoAbs,
 	}
 	if report == nil && useLLM {
 		if summarizePerPR {
-			fmt.Fprintf(os.Stderr, "✓ Using LLM (%s) per-PR (include_diff=%v, cache=%v)\n", provider, includeDiff, cacheLLMSummaries)
+			fmt.Fprintf(os.Stderr, "✓ Using LLM (%s) per-PR (include_diff=%v, cache=%v, render_template=%v)\n", provider, includeDiff, cacheLLMSummaries, opts.RenderTemplate)
 		} else {
 			fmt.Fprintf(os.Stderr, "✓ Using LLM (%s) to generate changelog section\n", provider)
 		}
 	}
-	if useGitHub && useLLM && summarizePerPR && includeDiff && len(src.PRs) > 0 {
-		token := cfg.GitHub.Token
-		if token == "" {
-			token = os.Getenv("GITHUB_TOKEN")
+	if useForge && useLLM && summarizePerPR && includeDiff && len(src.PRs) > 0 {
+		gh, err := forgeChangeSource(ctx, cfg, repoAbs, fr)
+		if err != nil {
+			return err
+		}
+		if report != nil {
+			report(fmt.Sprintf("Fetching diffs for %d PR(s)...", len(src.PRs)))
 		}
-		gh := github.NewClient(ctx, token, owner, repo)
-		for i := range src.PRs {
+		// Each job writes only its own PR's Diff, so order is kept and no locking is needed.
+		_ = workpool.ForEach(ctx, len(src.PRs), concurrency, func(ctx context.Context, i int) error {
 			diff, err := gh.GetPRDiff(ctx, src.PRs[i].Number)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "warning: could not fetch diff for PR #%d: %v\n", src.PRs[i].Number, err)
-				continue
+				fmt.Fprintf(os.Stderr, "warning: could not fetch diff for #%d: %v\n", src.PRs[i].Number, err)
+				return nil
 			}
 			src.PRs[i].Diff = diff
-		}
+			return nil
+		}, reportProgress)
 		opts.Source = src
 	}
 	if data, err := os.ReadFile(outPath); err == nil {
@@ -346,7 +362,7 @@ func generateChangelogSection(ctx context.Context, cfg *config.Config, repoAbs,
 }
 
 // resolveChangelogSource returns whether to use PRs and/or git history for the changelog.
-// Flags override config. When both would be true, PRs win. When neither is set, default is PRs if github.enabled.
+// Flags override config. When both would be true, PRs win. When neither is set, default is PRs if github.enabled or gitlab.enabled.
 func resolveChangelogSource(cfg *config.Config, usePRsFlag, useHistoryFlag bool) (usePRs, useHistory bool) {
 	usePRs = usePRsFlag
 	useHistory = useHistoryFlag
@@ -362,7 +378,7 @@ func resolveChangelogSource(cfg *config.Config, usePRsFlag, useHistoryFlag bool)
 		useHistory = false
 	}
 	if !usePRs && !useHistory {
-		usePRs = cfg.GitHub != nil && cfg.GitHub.Enabled
+		usePRs = cfg.PRsEnabled()
 	}
 	return usePRs, useHistory
 }
@@ -372,25 +388,23 @@ func resolveChangelogSource(cfg *config.Config, usePRsFlag, useHistoryFlag bool)
 // If reportProgress is non-nil (current, total), it is used during GitHub PR fetch.
 func gatherChangelogSource(ctx context.Context, cfg *config.Config, repoAbs, prev, headRef string, prLimit int, usePRs, useHistory bool, report func(string), reportProgress func(current, total int)) (changelog.Source, error) {
 	var src changelog.Source
-	useGitHub := usePRs && cfg.GitHub != nil && cfg.GitHub.Enabled
-	if usePRs && (cfg.GitHub == nil || !cfg.GitHub.Enabled) {
-		return src, fmt.Errorf("use_prs or --use-prs requires github.enabled in config")
-	}
-	if useGitHub {
-		owner := cfg.GitHub.Owner
-		repo := cfg.GitHub.Repo
-		if owner == "" || repo == "" {
-			remote, err := git.RemoteOriginURL(ctx, repoAbs)
-			if err != nil {
-				return src, fmt.Errorf("github not configured and could not get remote: %w", err)
-			}
-			owner, repo, err = git.ParseGitHubOwnerRepo(remote)
-			if err != nil {
-				return src, err
-			}
+	if usePRs && !cfg.PRsEnabled() {
+		return src, fmt.Errorf("use_prs or --use-prs requires github.enabled or gitlab.enabled in config")
+	}
+	if usePRs {
+		fr, err := resolveForgeRepo(ctx, cfg, repoAbs, defaultRemote(cfg))
+		if err != nil {
+			return src, err
+		}
+		owner, repo := fr.owner, fr.repo
+		// With a component selected, only PRs of commits touching its paths are included (cached separately).
+		paths := cfg.ComponentPaths()
+		cacheHead := headRef
+		if len(paths) > 0 {
+			cacheHead += " -- " + strings.Join(paths, " ")
 		}
 		prCache := cache.NewPRCache(filepath.Join(repoAbs, cache.DefaultDir))
-		if prs, ok := prCache.Get(owner, repo, prev, headRef); ok {
+		if prs, ok := prCache.Get(owner, repo, prev, cacheHead); ok {
 			src.PRs = prs
 			if prLimit > 0 && len(src.PRs) > prLimit {
 				src.PRs = src.PRs[:prLimit]
@@ -399,22 +413,35 @@ func gatherChangelogSource(ctx context.Context, cfg *config.Config, repoAbs, pre
 				report(fmt.Sprintf("Found %d PRs in that range.", len(src.PRs)))
 			}
 		} else {
-			token := cfg.GitHub.Token
-			if token == "" {
-				token = os.Getenv("GITHUB_TOKEN")
+			gh, err := forgeChangeSource(ctx, cfg, repoAbs, fr)
+			if err != nil {
+				return src, err
 			}
-			gh := github.NewClient(ctx, token, owner, repo)
 			var prs []github.PullRequest
 			var errGH error
-			if report != nil || reportProgress != nil {
-				prs, errGH = gh.MergedPRsBetweenWithProgress(ctx, prev, headRef, report, reportProgress)
+			if len(paths) > 0 {
+				shas, err := git.CommitSHAsBetween(ctx, repoAbs, prev, headRef, paths...)
+				if err != nil {
+					return src, err
+				}
+				prs, errGH = gh.MergedPRsForCommits(ctx, shas, report, reportProgress)
 			} else {
-				prs, errGH = gh.MergedPRsBetween(ctx, prev, headRef)
+				prs, errGH = gh.MergedPRsBetweenWithProgress(ctx, prev, headRef, report, reportProgress)
 			}
-			if errGH != nil {
-				return src, fmt.Errorf("github merged PRs: %w", errGH)
+			// Commits whose PRs could not be looked up are skipped with a warning; the partial result is not cached.
+			var lookupErr *github.PRLookupError
+			if errors.As(errGH, &lookupErr) {
+				msg := "Warning: " + lookupErr.Error()
+				if report != nil {
+					report(msg)
+				} else {
+					fmt.Fprintln(os.Stderr, msg)
+				}
+			} else if errGH != nil {
+				return src, fmt.Errorf("%s merged PRs: %w", fr.kind, errGH)
+			} else {
+				_ = prCache.Set(owner, repo, prev, cacheHead, prs)
 			}
-			_ = prCache.Set(owner, repo, prev, headRef, prs)
 			src.PRs = prs
 			if report != nil {
 				report(fmt.Sprintf("Found %d PRs in that range.", len(src.PRs)))
@@ -430,7 +457,7 @@ func gatherChangelogSource(ctx context.Context, cfg *config.Config, repoAbs, pre
 		if report != nil {
 			report("Reading git log between " + prev + " and " + headRef + "...")
 		}
-		commits, err := git.LogBetween(ctx, repoAbs, prev, headRef)
+		commits, err := git.LogBetween(ctx, repoAbs, prev, headRef, cfg.ComponentPaths()...)
 		if err != nil {
 			return src, fmt.Errorf("git log: %w", err)
 		}
@@ -488,6 +515,35 @@ func resolveLLMConfig(cfg *config.Config) (provider, model, baseURL string) {
 	return provider, model, baseURL
 }
 
+// resolveRenderTemplate returns llm.render_template (changelog.llm first, then top-level llm).
+func resolveRenderTemplate(cfg *config.Config) bool {
+	if cfg.Changelog != nil && cfg.Changelog.LLM != nil {
+		return cfg.Changelog.LLM.RenderTemplate
+	}
+	return cfg.LLM != nil && cfg.LLM.RenderTemplate
+}
+
+// defaultLLMConcurrency is the number of PRs summarized in parallel when llm.concurrency is not set.
+const defaultLLMConcurrency = 4
+
+// resolveLLMConcurrency returns llm.concurrency (default defaultLLMConcurrency) and llm.requests_per_minute.
+func resolveLLMConcurrency(cfg *config.Config) (concurrency, rpm int) {
+	var llm *config.LLMConfig
+	if cfg.Changelog != nil && cfg.Changelog.LLM != nil {
+		llm = cfg.Changelog.LLM
+	} else if cfg.LLM != nil {
+		llm = cfg.LLM
+	}
+	if llm == nil {
+		return defaultLLMConcurrency, 0
+	}
+	concurrency = llm.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultLLMConcurrency
+	}
+	return concurrency, llm.RequestsPerMinute
+}
+
 // resolvePerPRConfig returns summarize_per_pr, include_diff, cache_llm_summaries from config.
 func resolvePerPRConfig(cfg *config.Config) (summarizePerPR, includeDiff, cacheLLMSummaries bool) {
 	var llm *config.LLMConfig
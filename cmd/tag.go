@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/johnewart/releasebot/internal/cache"
+	"github.com/johnewart/releasebot/internal/changelog"
+	"github.com/johnewart/releasebot/internal/config"
 	"github.com/johnewart/releasebot/internal/git"
 	"github.com/johnewart/releasebot/internal/semver"
 	"github.com/spf13/cobra"
@@ -17,6 +20,7 @@ var (
 	tagNextRelease bool
 	tagNextMajor   bool
 	tagNextCreate  bool
+	tagNextAuto    bool
 )
 
 var tagCmd = &cobra.Command{
@@ -38,9 +42,21 @@ With --rc: prints the next release candidate tag (X.Y.ZrcN).
 
 With --alpha: same as --rc but for alpha prereleases (X.Y.ZaN).
 
+With tag_scheme: semver2 in config, tags follow SemVer 2.0 instead: --rc and --alpha print
+vX.Y.Z-rc.N and vX.Y.Z-alpha.N (starting at 1), and tags with other prerelease identifiers
+(e.g. v1.4.0-beta.2) or build metadata (+build.7) are ordered by SemVer 2.0 precedence.
+
 With --release: next minor version (e.g. v2.78.0 if latest is 2.77.x).
 With --release --major: next major version (e.g. v3.0.0 if latest is 2.77.x).
 
+With --auto: pick the bump from the changes since the previous release, gathered like the
+changelog (PRs or commits): a breaking change (feat!:, BREAKING CHANGE:) or a Removed entry gives
+a major bump, an Added entry (feat:) a minor bump, anything else a patch. PRs use their cached
+per-PR LLM change type when available, otherwise their title. The reasoning is printed to stderr.
+
+With --component NAME: only tags with the component's tag_prefix are considered and the
+prefix is added to the result (e.g. api/v1.2.4).
+
 With --create: create the tag in the repo (annotated tag at HEAD) and print it.
 With --dry-run and --create: print the tag that would be created without creating it.`,
 	RunE: runTagNext,
@@ -54,6 +70,8 @@ func init() {
 	tagNextCmd.Flags().BoolVar(&tagNextRelease, "release", false, "next minor release (X.Y+1.0)")
 	tagNextCmd.Flags().BoolVar(&tagNextMajor, "major", false, "with --release, next major version (X+1.0.0)")
 	tagNextCmd.Flags().BoolVar(&tagNextCreate, "create", false, "create the tag in the repo (annotated tag at HEAD) and print it")
+	tagNextCmd.Flags().BoolVar(&tagNextAuto, "auto", false, "infer patch/minor/major from the changes since the previous release (breaking or Removed → major, Added → minor)")
+	tagNextCmd.Flags().StringVar(&component, "component", "", "monorepo component (from components in config): only its prefixed tags are considered")
 }
 
 func runTagNext(cmd *cobra.Command, args []string) error {
@@ -66,16 +84,48 @@ func runTagNext(cmd *cobra.Command, args []string) error {
 	if tagNextMajor && !tagNextRelease {
 		return fmt.Errorf("--major must be used with --release")
 	}
+	if tagNextAuto && (tagNextRC || tagNextAlpha || tagNextRelease || tagNextMajor) {
+		return fmt.Errorf("--auto picks the bump itself; cannot combine with --rc, --alpha, --release or --major")
+	}
 	repoAbs, err := filepath.Abs(repoPath)
 	if err != nil {
 		return fmt.Errorf("repo path: %w", err)
 	}
+	configPath := cfgFile
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(repoAbs, configPath)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	cfg.Resolve(repoAbs)
+	if err := cfg.UseComponent(component); err != nil {
+		return err
+	}
+	scheme, err := cfg.Scheme()
+	if err != nil {
+		return err
+	}
 	ctx := context.Background()
-	tags, err := git.ListTags(ctx, repoAbs)
+	tags, err := componentTags(ctx, repoAbs, cfg)
 	if err != nil {
 		return err
 	}
-	next := semver.NextFromTags(tags, tagNextRC, tagNextAlpha, tagNextRelease, tagNextMajor)
+	release, major := tagNextRelease, tagNextMajor
+	if tagNextAuto {
+		prev := cfg.PreviousReleaseTag
+		if prev == "" {
+			prev = semver.AddTagPrefix(cfg.TagPrefix(), semver.LatestStableTagScheme(tags, scheme))
+		}
+		var reason string
+		release, major, reason, err = autoBump(ctx, cfg, repoAbs, prev, "HEAD")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Auto bump %s\n", reason)
+	}
+	next := semver.AddTagPrefix(cfg.TagPrefix(), semver.NextFromTagsScheme(tags, scheme, tagNextRC, tagNextAlpha, release, major))
 	if tagNextCreate {
 		if dryRun {
 			fmt.Fprintf(os.Stderr, "[dry-run] Would create tag %s\n", next)
@@ -89,3 +139,49 @@ func runTagNext(cmd *cobra.Command, args []string) error {
 	fmt.Fprintln(os.Stdout, next)
 	return nil
 }
+
+// componentTags returns the repo's tags for the selected component (cfg.TagPrefix) with the prefix
+// stripped, so they parse as versions. Returns all tags when no component is selected.
+func componentTags(ctx context.Context, repoAbs string, cfg *config.Config) ([]string, error) {
+	tags, err := git.ListTags(ctx, repoAbs)
+	if err != nil {
+		return nil, err
+	}
+	return semver.TrimTagPrefix(tags, cfg.TagPrefix()), nil
+}
+
+// autoBump infers the version bump (--auto) from the changes between prev and head, gathered the same way as
+// the changelog (PRs or commits). PRs use their cached per-PR LLM summaries when available (no new LLM calls),
+// otherwise their titles are parsed as Conventional Commits. Returns the release/major flags for NextFromTags.
+func autoBump(ctx context.Context, cfg *config.Config, repoAbs, prev, head string) (release, major bool, reason string, err error) {
+	if prev == "" {
+		return false, false, "patch: no previous release tag", nil
+	}
+	usePRsRes, useHistoryRes := resolveChangelogSource(cfg, usePRs, useHistory)
+	src, err := gatherChangelogSource(ctx, cfg, repoAbs, prev, head, 0, usePRsRes, useHistoryRes, func(string) {}, nil)
+	if err != nil {
+		return false, false, "", fmt.Errorf("auto bump: %w", err)
+	}
+	var changes []changelog.ClassifiedChange
+	if len(src.PRs) > 0 {
+		summaries := make(map[int]*changelog.PRChange)
+		summarizePerPR, includeDiff, cacheLLMSummaries := resolvePerPRConfig(cfg)
+		if summarizePerPR && cacheLLMSummaries {
+			fr, _ := resolveForgeRepo(ctx, cfg, repoAbs, defaultRemote(cfg))
+			owner, repo := fr.owner, fr.repo
+			summaryCache := cache.NewLLMSummaryCache(filepath.Join(repoAbs, cache.DefaultDir, "llm_pr"))
+			for _, pr := range src.PRs {
+				if raw, ok := summaryCache.Get(owner, repo, pr.Number, includeDiff); ok {
+					if c, err := changelog.ParsePRChangeJSON(raw, pr.Number); err == nil {
+						summaries[pr.Number] = c
+					}
+				}
+			}
+		}
+		changes = changelog.ClassifyPRs(src.PRs, summaries)
+	} else {
+		changes = changelog.ClassifyCommits(src.Commits)
+	}
+	bump, reason := changelog.InferBump(changes)
+	return bump >= changelog.BumpMinor, bump == changelog.BumpMajor, reason, nil
+}
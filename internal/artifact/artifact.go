@@ -0,0 +1,43 @@
+// Package artifact defines the interface shared by package registry checkers (npm, crates.io, Maven, Go proxy)
+// and the poller every availability wait (including PyPI and container registries) is built on.
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Checker checks whether a package version is published on one registry.
+type Checker interface {
+	// Registry returns the registry host for messages (e.g. "registry.npmjs.org").
+	Registry() string
+	// Check returns true if the package exists. If version is non-empty, returns true only when that
+	// specific version is published. Returns false and nil when the registry does not have it.
+	Check(ctx context.Context, name, version string) (bool, error)
+}
+
+// Wait polls c until the package (and optionally version) exists or the context/timeout is exceeded (see Poll).
+// Returns nil when the package is available; returns an error on timeout or other failure.
+func Wait(ctx context.Context, c Checker, name, version string, opts WaitOptions) error {
+	return Poll(ctx, opts, func(ctx context.Context) (bool, string, error) {
+		ok, err := c.Check(ctx, name, version)
+		return ok, fmt.Sprintf("%s not available on %s", Ref(name, version), c.Registry()), err
+	})
+}
+
+// Ref returns name@version for messages (name alone when version is empty).
+func Ref(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
+
+// Host returns the host of rawURL for messages (rawURL itself when it has none).
+func Host(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
@@ -0,0 +1,98 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeChecker reports the package available from the given poll on.
+type fakeChecker struct {
+	availableAt int
+	polls       int
+}
+
+func (f *fakeChecker) Registry() string { return "registry.example.com" }
+
+func (f *fakeChecker) Check(ctx context.Context, name, version string) (bool, error) {
+	f.polls++
+	return f.availableAt > 0 && f.polls >= f.availableAt, nil
+}
+
+func TestWait(t *testing.T) {
+	opts := WaitOptions{Timeout: time.Second, Interval: time.Millisecond}
+	c := &fakeChecker{availableAt: 3}
+	if err := Wait(context.Background(), c, "pkg", "1.0.0", opts); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if c.polls != 3 {
+		t.Errorf("polls = %d, want 3", c.polls)
+	}
+
+	opts.Timeout = 20 * time.Millisecond
+	err := Wait(context.Background(), &fakeChecker{}, "pkg", "1.0.0", opts)
+	if err == nil || !strings.Contains(err.Error(), "pkg@1.0.0 not available on registry.example.com") {
+		t.Errorf("Wait error = %v, want timeout for pkg@1.0.0", err)
+	}
+}
+
+func TestPoll_TransientErrors(t *testing.T) {
+	var progress []Progress
+	opts := WaitOptions{Timeout: time.Second, Interval: time.Millisecond, Progress: func(p Progress) { progress = append(progress, p) }}
+	attempt := 0
+	err := Poll(context.Background(), opts, func(ctx context.Context) (bool, string, error) {
+		attempt++
+		switch attempt {
+		case 1:
+			return false, "", &StatusError{Host: "registry.example.com", StatusCode: 503, Resource: "pkg"}
+		case 2:
+			return false, "pkg not available", nil
+		}
+		return true, "", nil
+	})
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(progress) != 2 || progress[0].Err == nil || progress[1].Reason != "pkg not available" || progress[1].Attempt != 2 {
+		t.Errorf("progress = %+v", progress)
+	}
+
+	err = Poll(context.Background(), opts, func(ctx context.Context) (bool, string, error) {
+		return false, "", &StatusError{Host: "registry.example.com", StatusCode: 401, Resource: "pkg"}
+	})
+	if err == nil || !strings.Contains(err.Error(), "returned 401") {
+		t.Errorf("Poll with 401 = %v, want the error without retrying", err)
+	}
+}
+
+func TestPoll_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := WaitOptions{Timeout: time.Minute, Interval: time.Millisecond, Progress: func(Progress) { cancel() }}
+	err := Poll(ctx, opts, func(ctx context.Context) (bool, string, error) { return false, "missing", nil })
+	if err != context.Canceled {
+		t.Errorf("Poll = %v, want context.Canceled", err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{StatusCode: 500}, true},
+		{&StatusError{StatusCode: 429}, true},
+		{&StatusError{StatusCode: 403}, false},
+		{&url.Error{Op: "Get", URL: "https://x", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{&url.Error{Op: "Get", URL: "ftp://x", Err: errors.New("unsupported protocol scheme")}, false},
+		{context.Canceled, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WaitOptions configures Poll (and the Wait functions built on it).
+type WaitOptions struct {
+	// Timeout is the maximum time to wait (default 5m).
+	Timeout time.Duration
+	// Interval is the delay before the second check (default 5s). Later delays grow by half each time.
+	Interval time.Duration
+	// MaxInterval caps the delay between checks (default 30s, or Interval when that is larger).
+	MaxInterval time.Duration
+	// Progress, when set, is called after every check that did not succeed.
+	Progress func(Progress)
+}
+
+// DefaultWaitOptions returns defaults: 5m timeout, 5s first interval, 30s max interval.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Timeout:     5 * time.Minute,
+		Interval:    5 * time.Second,
+		MaxInterval: 30 * time.Second,
+	}
+}
+
+// Progress describes a check that did not succeed, for rendering while waiting.
+type Progress struct {
+	// Attempt is the number of checks so far (1-based).
+	Attempt int
+	// Elapsed is the time since the wait started.
+	Elapsed time.Duration
+	// Next is the delay before the next check.
+	Next time.Duration
+	// Reason says what is still missing (e.g. "my-pkg@1.2.3 not available on registry.npmjs.org").
+	Reason string
+	// Err is the transient error of this check, when it failed (it is retried).
+	Err error
+}
+
+// String returns a one-line summary, e.g. "attempt 3 (42s): missing cp312 wheel; next check in 11s".
+func (p Progress) String() string {
+	what := p.Reason
+	if p.Err != nil {
+		what = "error: " + p.Err.Error()
+	}
+	return fmt.Sprintf("attempt %d (%s): %s; next check in %s", p.Attempt, p.Elapsed.Round(time.Second), what, p.Next.Round(time.Second))
+}
+
+// CheckFunc reports whether the awaited condition holds. When it does not, reason says what is still missing
+// (for progress and the timeout error). Transient errors (see IsTransient) are retried; others end the wait.
+type CheckFunc func(ctx context.Context) (done bool, reason string, err error)
+
+// Poll calls check until it reports done, the timeout is exceeded, the context is canceled, or check returns a
+// non-transient error. Delays between checks back off exponentially from opts.Interval to opts.MaxInterval with
+// ±20% jitter, and the last check happens at the deadline.
+func Poll(ctx context.Context, opts WaitOptions, check CheckFunc) error {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 30 * time.Second
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	start := time.Now()
+	deadline := start.Add(opts.Timeout)
+	delay := opts.Interval
+	var reason string
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		done, r, err := check(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil && !IsTransient(err) {
+			return err
+		}
+		if err == nil && done {
+			return nil
+		}
+		if err == nil {
+			reason, lastErr = r, nil
+		} else {
+			lastErr = err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return timeoutError(reason, lastErr, opts.Timeout)
+		}
+		next := jitter(delay)
+		if next > remaining {
+			next = remaining
+		}
+		if opts.Progress != nil {
+			opts.Progress(Progress{Attempt: attempt, Elapsed: time.Since(start), Next: next, Reason: reason, Err: lastErr})
+		}
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if delay = delay * 3 / 2; delay > opts.MaxInterval {
+			delay = opts.MaxInterval
+		}
+	}
+}
+
+// timeoutError reports what was still missing at the deadline, and the last check's error when it failed.
+func timeoutError(reason string, lastErr error, timeout time.Duration) error {
+	if reason == "" {
+		reason = "not available"
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%s after %v (last error: %w)", reason, timeout, lastErr)
+	}
+	return fmt.Errorf("%s after %v", reason, timeout)
+}
+
+// jitter returns d ± 20%.
+func jitter(d time.Duration) time.Duration {
+	return d + time.Duration((rand.Float64()*0.4-0.2)*float64(d))
+}
+
+// StatusError is an unexpected HTTP status from a registry. Server errors and 429 are transient.
+type StatusError struct {
+	// Host is the registry host (e.g. pypi.org).
+	Host string
+	// StatusCode is the HTTP status.
+	StatusCode int
+	// Resource is what was requested (a URL, package or manifest reference).
+	Resource string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %d for %s", e.Host, e.StatusCode, e.Resource)
+}
+
+// IsTransient returns true for errors worth retrying: 5xx and 429 responses (StatusError), network errors
+// (connection refused or reset, timeouts, DNS failures) and truncated responses. Context cancellation is not.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= http.StatusInternalServerError || se.StatusCode == http.StatusTooManyRequests
+	}
+	// *url.Error (every http.Client failure) is itself a net.Error; classify what it wraps, so a bad scheme
+	// or certificate is not retried.
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		err = ue.Err
+	}
+	var ne net.Error
+	return errors.As(err, &ne) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
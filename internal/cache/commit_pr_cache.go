@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/johnewart/releasebot/internal/github"
+)
+
+// CommitPRCache stores the merged PRs associated with each commit SHA (e.g. .releasebot/cache/commit_prs),
+// so overlapping ranges only look up new commits. It implements github.CommitPRCache.
+// Only commits with at least one merged PR are stored, since a commit's PR may not be merged yet.
+type CommitPRCache struct {
+	Dir string
+}
+
+// NewCommitPRCache uses dir. Dir is created on first Set.
+func NewCommitPRCache(dir string) *CommitPRCache {
+	return &CommitPRCache{Dir: dir}
+}
+
+func commitKey(owner, repo, sha string) string {
+	return key(owner, repo, sha, "prs")
+}
+
+// GetCommitPRs returns the cached PRs for sha. Returns (nil, false) on miss or error.
+func (c *CommitPRCache) GetCommitPRs(owner, repo, sha string) ([]github.PullRequest, bool) {
+	data, err := os.ReadFile(filepath.Join(c.Dir, commitKey(owner, repo, sha)))
+	if err != nil {
+		return nil, false
+	}
+	var prs []github.PullRequest
+	if err := json.Unmarshal(data, &prs); err != nil {
+		return nil, false
+	}
+	return prs, true
+}
+
+// SetCommitPRs stores prs for sha. Empty results are not stored.
+func (c *CommitPRCache) SetCommitPRs(owner, repo, sha string, prs []github.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	data, err := json.Marshal(prs)
+	if err != nil {
+		return fmt.Errorf("marshal cache: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.Dir, commitKey(owner, repo, sha)), data, 0644); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	return nil
+}
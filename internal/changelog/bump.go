@@ -0,0 +1,109 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/github"
+)
+
+// Bump is the version component to increment for the next release.
+type Bump int
+
+const (
+	BumpPatch Bump = iota
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpMajor:
+		return "major"
+	case BumpMinor:
+		return "minor"
+	}
+	return "patch"
+}
+
+// ClassifiedChange is one PR or commit with its change type, used to infer the version bump.
+type ClassifiedChange struct {
+	Ref        string // "#12" for a PR, short SHA for a commit
+	ChangeType string // one of ValidChangeTypes
+	Breaking   bool
+}
+
+// ClassifyCommits classifies commits by their Conventional Commit type (non-conventional commits are "Changed").
+func ClassifyCommits(commits []git.Commit) []ClassifiedChange {
+	out := make([]ClassifiedChange, 0, len(commits))
+	for _, c := range commits {
+		ch := ClassifiedChange{Ref: shortCommitSHA(c.SHA), ChangeType: "Changed"}
+		if cc, ok := ParseConventionalCommit(c.Subject, c.Body); ok {
+			ch.ChangeType = cc.ChangeType()
+			ch.Breaking = cc.Breaking
+		}
+		out = append(out, ch)
+	}
+	return out
+}
+
+// ClassifyPRs classifies PRs using their per-PR LLM summaries when available (summaries by PR number, may be nil),
+// otherwise by parsing the PR title as a Conventional Commit. A BREAKING CHANGE footer in the body marks a PR as breaking.
+func ClassifyPRs(prs []github.PullRequest, summaries map[int]*PRChange) []ClassifiedChange {
+	out := make([]ClassifiedChange, 0, len(prs))
+	for _, pr := range prs {
+		ch := ClassifiedChange{Ref: fmt.Sprintf("#%d", pr.Number), ChangeType: "Changed"}
+		cc, ok := ParseConventionalCommit(pr.Title, pr.Body)
+		if ok {
+			ch.ChangeType = cc.ChangeType()
+			ch.Breaking = cc.Breaking
+		} else if breakingFooterRegex.MatchString(pr.Body) {
+			ch.Breaking = true
+		}
+		if s := summaries[pr.Number]; s != nil {
+			ch.ChangeType = s.ChangeType
+		}
+		out = append(out, ch)
+	}
+	return out
+}
+
+// InferBump picks the bump for changes: any breaking change or Removed entry → major, any Added entry → minor,
+// otherwise patch. The returned reason names the changes that decided it (e.g. "minor: 2 Added (#12, #15)").
+func InferBump(changes []ClassifiedChange) (Bump, string) {
+	var breaking, removed, added []string
+	for _, c := range changes {
+		switch {
+		case c.Breaking:
+			breaking = append(breaking, c.Ref)
+		case c.ChangeType == "Removed":
+			removed = append(removed, c.Ref)
+		case c.ChangeType == "Added":
+			added = append(added, c.Ref)
+		}
+	}
+	if len(breaking) > 0 || len(removed) > 0 {
+		var parts []string
+		if len(breaking) > 0 {
+			parts = append(parts, fmt.Sprintf("%d breaking (%s)", len(breaking), refList(breaking)))
+		}
+		if len(removed) > 0 {
+			parts = append(parts, fmt.Sprintf("%d Removed (%s)", len(removed), refList(removed)))
+		}
+		return BumpMajor, "major: " + strings.Join(parts, ", ")
+	}
+	if len(added) > 0 {
+		return BumpMinor, fmt.Sprintf("minor: %d Added (%s)", len(added), refList(added))
+	}
+	return BumpPatch, fmt.Sprintf("patch: no breaking, Removed or Added changes in %d change(s)", len(changes))
+}
+
+// refList joins refs for display, eliding after the first few.
+func refList(refs []string) string {
+	const max = 5
+	if len(refs) <= max {
+		return strings.Join(refs, ", ")
+	}
+	return strings.Join(refs[:max], ", ") + fmt.Sprintf(", +%d more", len(refs)-max)
+}
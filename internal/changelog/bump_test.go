@@ -0,0 +1,51 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/github"
+)
+
+func TestInferBump(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []ClassifiedChange
+		want    Bump
+		reason  string
+	}{
+		{"empty", nil, BumpPatch, "patch: no breaking, Removed or Added changes in 0 change(s)"},
+		{"fixes", []ClassifiedChange{{Ref: "#1", ChangeType: "Fixed"}, {Ref: "#2", ChangeType: "Changed"}}, BumpPatch, "patch: no breaking, Removed or Added changes in 2 change(s)"},
+		{"added", []ClassifiedChange{{Ref: "#1", ChangeType: "Fixed"}, {Ref: "#2", ChangeType: "Added"}}, BumpMinor, "minor: 1 Added (#2)"},
+		{"removed", []ClassifiedChange{{Ref: "#2", ChangeType: "Added"}, {Ref: "#3", ChangeType: "Removed"}}, BumpMajor, "major: 1 Removed (#3)"},
+		{"breaking", []ClassifiedChange{{Ref: "abc1234", ChangeType: "Changed", Breaking: true}, {Ref: "#3", ChangeType: "Removed"}}, BumpMajor, "major: 1 breaking (abc1234), 1 Removed (#3)"},
+		{"many", []ClassifiedChange{
+			{Ref: "#1", ChangeType: "Added"}, {Ref: "#2", ChangeType: "Added"}, {Ref: "#3", ChangeType: "Added"},
+			{Ref: "#4", ChangeType: "Added"}, {Ref: "#5", ChangeType: "Added"}, {Ref: "#6", ChangeType: "Added"},
+		}, BumpMinor, "minor: 6 Added (#1, #2, #3, #4, #5, +1 more)"},
+	}
+	for _, tt := range tests {
+		got, reason := InferBump(tt.changes)
+		if got != tt.want || reason != tt.reason {
+			t.Errorf("%s: InferBump = %s, %q; want %s, %q", tt.name, got, reason, tt.want, tt.reason)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	commits := ClassifyCommits([]git.Commit{
+		{SHA: "aaaaaaa111", Subject: "feat!: new config format"},
+		{SHA: "bbbbbbb222", Subject: "Fix typo"},
+	})
+	if !commits[0].Breaking || commits[0].ChangeType != "Added" || commits[0].Ref != "aaaaaaa" || commits[1].ChangeType != "Changed" {
+		t.Errorf("ClassifyCommits = %+v", commits)
+	}
+	prs := ClassifyPRs([]github.PullRequest{
+		{Number: 1, Title: "feat: add x"},
+		{Number: 2, Title: "Drop the v1 API", Body: "BREAKING CHANGE: v1 endpoints are gone"},
+		{Number: 3, Title: "Tidy things"},
+	}, map[int]*PRChange{3: {ChangeType: "Removed", PRID: 3}})
+	if prs[0].ChangeType != "Added" || !prs[1].Breaking || prs[2].ChangeType != "Removed" || prs[2].Ref != "#3" {
+		t.Errorf("ClassifyPRs = %+v", prs)
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/johnewart/releasebot/internal/cache"
 	"github.com/johnewart/releasebot/internal/git"
 	"github.com/johnewart/releasebot/internal/github"
+	"github.com/johnewart/releasebot/internal/workpool"
 )
 
 // Source is either GitHub PRs or git commits.
@@ -32,9 +34,17 @@ type GenerateOptions struct {
 	// Per-PR summarization: when true, analyze each PR independently with the LLM (one call per PR → JSON),
 	// then build the final changelog from that JSON (template). Reduces context/scope per call. When false,
 	// feed the LLM all PRs at once in a single call (may take longer but more contextually relevant).
-	SummarizePerPR     bool
-	IncludeDiff        bool // when true, pass PR diff to LLM (only when SummarizePerPR)
-	CacheLLMSummaries  bool // when true, use LLMSummaryCacheDir to cache per-PR summaries
+	SummarizePerPR    bool
+	IncludeDiff       bool // when true, pass PR diff to LLM (only when SummarizePerPR)
+	CacheLLMSummaries bool // when true, use LLMSummaryCacheDir to cache per-PR summaries
+	// RenderTemplate: when true (with SummarizePerPR), the per-PR records are rendered through ChangelogWriterTemplate
+	// with text/template and ChangelogTemplateData instead of a final LLM call.
+	RenderTemplate bool
+	// Concurrency is the number of PRs summarized in parallel (<= 1 means sequential).
+	Concurrency int
+	// RequestsPerMinute caps SummarizePR calls per minute for LLMProvider, shared by all generations in
+	// this process (0 = unlimited). Cached summaries do not count.
+	RequestsPerMinute  int
 	LLMSummaryCacheDir string
 	Owner              string
 	Repo               string
@@ -42,13 +52,17 @@ type GenerateOptions struct {
 	// The LLM receives the summarized records (not raw PRs/diffs) and this template to produce the section.
 	ChangelogWriterTemplate string
 	RepoURL                 string // e.g. https://github.com/owner/repo for PR links
+	// PRURLFormat is the fmt format of a PR link with one %d for the number (default RepoURL + "/pull/%d";
+	// GitLab merge requests use RepoURL + "/-/merge_requests/%d").
+	PRURLFormat string
 	// ReportLLMProgress, when non-nil, is called with progress messages during LLM work (e.g. "Generating changelog section...").
 	ReportLLMProgress func(message string)
 	// ReportLLMProgressBar, when non-nil, is called with (current, total) during per-PR summarization for a progress bar instead of per-PR text.
 	ReportLLMProgressBar func(current, total int)
 }
 
-// Generate writes a new changelog section. If UseLLM is true, uses the LLM; otherwise formats entries with the template.
+// Generate writes a new changelog section. If UseLLM is true, uses the LLM; otherwise formats entries with the template
+// (git commits are grouped by Conventional Commit type into ChangelogWriterTemplate when it is set).
 // When SummarizePerPR is true: each PR is analyzed independently (LLM → JSON, cached); then the LLM is called
 // once with those summarized records (description, pr_id, change_type) to generate the changelog. When false:
 // all raw PRs are fed to the LLM in one call to generate the changelog.
@@ -102,19 +116,19 @@ func Generate(ctx context.Context, opts GenerateOptions) (string, error) {
 			if err != nil {
 				return "", fmt.Errorf("generate section: %w", err)
 			}
+		} else if len(opts.Source.PRs) == 0 && opts.ChangelogWriterTemplate != "" {
+			// Git history without an LLM: group commits by Conventional Commit type and render the template.
+			var err error
+			section, err = RenderTemplate(opts.ChangelogWriterTemplate, CommitTemplateData(opts.Version, opts.RepoURL, opts.Source.Commits).withPRURLs(opts.PRURLFormat))
+			if err != nil {
+				return "", err
+			}
 		} else {
 			section = formatSectionSimple(opts.Version, opts.Format, opts.Source)
 		}
 	}
 
-	section = strings.TrimSpace(section)
-	if !strings.HasSuffix(section, "\n") {
-		section += "\n"
-	}
-	full := section
-	if opts.ExistingHead != "" {
-		full = section + "\n" + opts.ExistingHead
-	}
+	full := Prepend(section, opts.ExistingHead)
 	if opts.OutputPath != "" {
 		if err := os.WriteFile(opts.OutputPath, []byte(full), 0644); err != nil {
 			return "", fmt.Errorf("write changelog: %w", err)
@@ -125,6 +139,7 @@ func Generate(ctx context.Context, opts GenerateOptions) (string, error) {
 
 // generateSectionPerPR analyzes each PR independently (LLM → JSON per PR, cached to file), then calls the LLM
 // once with those summarized records (description, pr_id, change_type) to generate the final changelog—not raw PRs or diffs.
+// With RenderTemplate, the records are rendered through the template locally and the final LLM call is skipped.
 func generateSectionPerPR(ctx context.Context, opts GenerateOptions) (string, error) {
 	if opts.ReportLLMProgress != nil {
 		opts.ReportLLMProgress("Generating summaries...")
@@ -139,17 +154,13 @@ func generateSectionPerPR(ctx context.Context, opts GenerateOptions) (string, er
 	}
 	withDiff := opts.IncludeDiff
 
-	var changes []*PRChange
+	// Summarize PRs on a bounded worker pool; results are stored by index so PR order is preserved.
 	total := len(opts.Source.PRs)
-	for i, pr := range opts.Source.PRs {
-		if opts.ReportLLMProgressBar != nil {
-			opts.ReportLLMProgressBar(i+1, total)
-		} else if opts.ReportLLMProgress != nil {
-			opts.ReportLLMProgress(fmt.Sprintf("Summarizing PR %d/%d", i+1, total))
-		}
+	changes := make([]*PRChange, total)
+	limiter := providerLimiter(opts.LLMProvider, opts.RequestsPerMinute)
+	err = workpool.ForEach(ctx, total, opts.Concurrency, func(ctx context.Context, i int) error {
+		pr := opts.Source.PRs[i]
 		metadata := fmt.Sprintf("Title: %s\nAuthor: @%s\nMerged: %s\n\nDescription:\n%s", pr.Title, pr.Author, pr.MergedAt, pr.Body)
-		diff := pr.Diff
-
 		var raw string
 		if summaryCache != nil {
 			if s, ok := summaryCache.Get(opts.Owner, opts.Repo, pr.Number, withDiff); ok {
@@ -157,9 +168,13 @@ func generateSectionPerPR(ctx context.Context, opts GenerateOptions) (string, er
 			}
 		}
 		if raw == "" {
-			raw, err = llm.SummarizePR(ctx, metadata, diff, pr.Number)
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+			var err error
+			raw, err = llm.SummarizePR(ctx, metadata, pr.Diff, pr.Number)
 			if err != nil {
-				return "", fmt.Errorf("summarize PR #%d: %w", pr.Number, err)
+				return fmt.Errorf("summarize PR #%d: %w", pr.Number, err)
 			}
 			if summaryCache != nil {
 				_ = summaryCache.Set(opts.Owner, opts.Repo, pr.Number, withDiff, raw)
@@ -167,9 +182,26 @@ func generateSectionPerPR(ctx context.Context, opts GenerateOptions) (string, er
 		}
 		c, err := ParsePRChangeJSON(raw, pr.Number)
 		if err != nil {
-			return "", fmt.Errorf("parse PR #%d response: %w", pr.Number, err)
+			return fmt.Errorf("parse PR #%d response: %w", pr.Number, err)
 		}
-		changes = append(changes, c)
+		changes[i] = c
+		return nil
+	}, func(completed, total int) {
+		if opts.ReportLLMProgressBar != nil {
+			opts.ReportLLMProgressBar(completed, total)
+		} else if opts.ReportLLMProgress != nil {
+			opts.ReportLLMProgress(fmt.Sprintf("Summarized PR %d/%d", completed, total))
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if opts.RenderTemplate && opts.ChangelogWriterTemplate != "" {
+		if opts.ReportLLMProgress != nil {
+			opts.ReportLLMProgress("Rendering changelog template...")
+		}
+		return RenderTemplate(opts.ChangelogWriterTemplate, PRTemplateData(opts.Version, opts.RepoURL, changes).withPRURLs(opts.PRURLFormat))
 	}
 
 	// Pass summarized records (not raw PRs/diffs) to the LLM to generate the changelog section.
@@ -180,7 +212,7 @@ func generateSectionPerPR(ctx context.Context, opts GenerateOptions) (string, er
 		}
 		opts.ReportLLMProgress(fmt.Sprintf("Combining changelog entries to create the new %s...", changelogName))
 	}
-	entries := formatSummarizedChanges(opts.RepoURL, changes)
+	entries := formatSummarizedChanges(opts.prURLFormat(), changes)
 	structure := opts.ChangelogWriterTemplate
 	if structure == "" {
 		structure = opts.Format
@@ -192,19 +224,52 @@ func generateSectionPerPR(ctx context.Context, opts GenerateOptions) (string, er
 	return section, nil
 }
 
+var (
+	providerLimitersMu sync.Mutex
+	providerLimiters   = make(map[string]*workpool.Limiter)
+)
+
+// providerLimiter returns the shared requests-per-minute limiter for provider (nil when rpm <= 0).
+func providerLimiter(provider string, rpm int) *workpool.Limiter {
+	if rpm <= 0 {
+		return nil
+	}
+	providerLimitersMu.Lock()
+	defer providerLimitersMu.Unlock()
+	key := fmt.Sprintf("%s/%d", provider, rpm)
+	l, ok := providerLimiters[key]
+	if !ok {
+		l = workpool.NewLimiter(rpm)
+		providerLimiters[key] = l
+	}
+	return l
+}
+
+// prURLFormat returns PRURLFormat, or the GitHub pull request links of RepoURL ("" without a RepoURL).
+func (opts GenerateOptions) prURLFormat() string {
+	if opts.PRURLFormat != "" || opts.RepoURL == "" {
+		return opts.PRURLFormat
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(opts.RepoURL, "/"), "%", "%%") + "/pull/%d"
+}
+
 // formatSummarizedChanges returns a string representation of per-PR summaries for the LLM to turn into a changelog section.
-func formatSummarizedChanges(repoURL string, changes []*PRChange) string {
+// prURLFormat (see GenerateOptions.PRURLFormat) links each PR when set.
+func formatSummarizedChanges(prURLFormat string, changes []*PRChange) string {
 	sections := make(map[string][]*PRChange)
 	for _, c := range changes {
 		sections[c.ChangeType] = append(sections[c.ChangeType], c)
 	}
 	var b strings.Builder
-	base := strings.TrimSuffix(repoURL, "/")
 	for _, typ := range ValidChangeTypes {
 		if list := sections[typ]; len(list) > 0 {
 			b.WriteString(typ + ":\n")
 			for _, c := range list {
-				b.WriteString(fmt.Sprintf("  - %s (#%d %s/pulls/%d)\n", c.Description, c.PRID, base, c.PRID))
+				if prURLFormat != "" {
+					b.WriteString(fmt.Sprintf("  - %s (#%d %s)\n", c.Description, c.PRID, fmt.Sprintf(prURLFormat, c.PRID)))
+				} else {
+					b.WriteString(fmt.Sprintf("  - %s (#%d)\n", c.Description, c.PRID))
+				}
 			}
 			b.WriteString("\n")
 		}
@@ -238,3 +303,43 @@ func formatSectionSimple(version, format string, src Source) string {
 	}
 	return b.String()
 }
+
+// Section returns the section for version from changelog content: the lines from the "## " heading that
+// names version up to the next "## " heading, with the heading itself dropped. Headings match with or
+// without a leading "v" (## [1.2.3] is the section for v1.2.3). Returns "" when no heading names version.
+func Section(content, version string) string {
+	if version == "" {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "## ") && headingHasVersion(l, version) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") {
+			end = i
+			break
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[start+1:end], "\n"))
+}
+
+// headingHasVersion returns true if one of the heading's words is version (ignoring surrounding brackets and
+// a leading "v" on either side).
+func headingHasVersion(heading, version string) bool {
+	version = strings.TrimPrefix(version, "v")
+	for _, f := range strings.Fields(heading) {
+		if strings.TrimPrefix(strings.Trim(f, "[]()"), "v") == version {
+			return true
+		}
+	}
+	return false
+}
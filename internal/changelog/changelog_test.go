@@ -0,0 +1,66 @@
+package changelog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/johnewart/releasebot/internal/git"
+)
+
+func TestSection(t *testing.T) {
+	content := "## [v1.2.0rc1]\n\n- Candidate\n\n## [v1.2.0] - 2024-05-01\n\n### Added\n\n- New thing\n\n## v1.1.0\n\n- Old thing\n"
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"v1.2.0", "### Added\n\n- New thing"},
+		{"v1.1.0", "- Old thing"},
+		{"v1.2.0rc1", "- Candidate"},
+		{"v9.9.9", ""},
+		{"1.1.0", "- Old thing"},
+	}
+	for _, tt := range tests {
+		if got := Section(content, tt.version); got != tt.want {
+			t.Errorf("Section(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+	if got, want := Section("## [1.2.3] - 2024-06-01\n\n- Fix\n\n## [1.2.2]\n\n- Older\n", "v1.2.3"), "- Fix"; got != want {
+		t.Errorf("Section(v1.2.3) with [1.2.3] heading = %q, want %q", got, want)
+	}
+	if got := Section("no headings", "v1.0.0"); got != "" {
+		t.Errorf("Section without headings = %q, want empty", got)
+	}
+}
+
+func TestGenerateHistory(t *testing.T) {
+	commits := []git.Commit{
+		{SHA: "1111111aaaa", Subject: "feat: add widget"},
+		{SHA: "2222222bbbb", Subject: "fix: widget crash"},
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		// With changelog.format set there is no writer template, so entries keep the simple format.
+		{"format", "", "## v1.1.0\n\n- feat: add widget (1111111)\n- fix: widget crash (2222222)\n"},
+		{"grouped", "## {{.Version}}\n{{range $s := .SectionOrder}}{{with index $.Sections $s}}\n### {{$s}}\n{{range .}}- {{.Description}}\n{{end}}{{end}}{{end}}",
+			"## v1.1.0\n\n### Added\n- add widget\n\n### Fixed\n- widget crash\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(context.Background(), GenerateOptions{
+				Version:                 "v1.1.0",
+				Format:                  "- {{.Title}} (#{{.Number}})",
+				Source:                  Source{Commits: commits},
+				ChangelogWriterTemplate: tt.template,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Generate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
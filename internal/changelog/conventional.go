@@ -0,0 +1,134 @@
+package changelog
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/johnewart/releasebot/internal/git"
+)
+
+// ConventionalCommit is a commit message parsed per Conventional Commits 1.0 (type(scope)!: description).
+type ConventionalCommit struct {
+	Type        string // e.g. feat, fix (lowercased)
+	Scope       string // optional, e.g. api in feat(api): ...
+	Description string
+	Breaking    bool   // "!" after the type/scope or a BREAKING CHANGE footer
+	BreakingMsg string // text of the BREAKING CHANGE footer, if any
+	PRID        int    // trailing "(#123)" in the subject (squash merges), 0 if none
+}
+
+var (
+	conventionalSubjectRegex = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?:\s+(.+)$`)
+	breakingFooterRegex      = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*(.*)$`)
+	trailingPRRegex          = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+)
+
+// conventionalChangeTypes maps Conventional Commit types to ValidChangeTypes sections. Unknown types map to "Changed".
+var conventionalChangeTypes = map[string]string{
+	"feat":      "Added",
+	"fix":       "Fixed",
+	"perf":      "Changed",
+	"refactor":  "Changed",
+	"revert":    "Changed",
+	"docs":      "Docs",
+	"deprecate": "Deprecated",
+	"remove":    "Removed",
+	"security":  "Security",
+	"build":     "Developer Experience",
+	"ci":        "Developer Experience",
+	"chore":     "Developer Experience",
+	"test":      "Developer Experience",
+	"style":     "Developer Experience",
+}
+
+// ParseConventionalCommit parses subject and body. Returns false if the subject is not a Conventional Commit.
+func ParseConventionalCommit(subject, body string) (*ConventionalCommit, bool) {
+	subject = strings.TrimSpace(subject)
+	m := conventionalSubjectRegex.FindStringSubmatch(subject)
+	if m == nil {
+		return nil, false
+	}
+	cc := &ConventionalCommit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Description: strings.TrimSpace(m[4]),
+		Breaking:    m[3] == "!",
+	}
+	if pm := trailingPRRegex.FindStringSubmatch(cc.Description); pm != nil {
+		cc.PRID, _ = strconv.Atoi(pm[1])
+		cc.Description = strings.TrimSpace(strings.TrimSuffix(cc.Description, pm[0]))
+	}
+	if fm := breakingFooterRegex.FindStringSubmatch(body); fm != nil {
+		cc.Breaking = true
+		cc.BreakingMsg = strings.TrimSpace(fm[1])
+	}
+	return cc, true
+}
+
+// ChangeType returns the ValidChangeTypes section for the commit type.
+func (cc *ConventionalCommit) ChangeType() string {
+	if t, ok := conventionalChangeTypes[cc.Type]; ok {
+		return t
+	}
+	return "Changed"
+}
+
+// CommitTemplateData groups commits into ChangelogTemplateData sections using their Conventional Commit
+// types. Commits that don't follow the convention go under "Changed" with their subject as description.
+// repoURL (e.g. https://github.com/owner/repo) is used for PR and commit links when set.
+func CommitTemplateData(version, repoURL string, commits []git.Commit) ChangelogTemplateData {
+	base := strings.TrimSuffix(repoURL, "/")
+	data := ChangelogTemplateData{
+		Version:      version,
+		RepoURL:      repoURL,
+		Sections:     make(map[string][]TemplateEntry),
+		SectionOrder: ValidChangeTypes,
+	}
+	for _, c := range commits {
+		entry := TemplateEntry{Description: strings.TrimSpace(c.Subject), SHA: shortCommitSHA(c.SHA)}
+		section := "Changed"
+		if cc, ok := ParseConventionalCommit(c.Subject, c.Body); ok {
+			section = cc.ChangeType()
+			entry.Description = cc.Description
+			if cc.Scope != "" {
+				entry.Description = "**" + cc.Scope + ":** " + cc.Description
+			}
+			entry.PRID = cc.PRID
+			entry.Breaking = cc.Breaking
+			entry.BreakingMsg = cc.BreakingMsg
+		}
+		if base != "" {
+			if entry.PRID > 0 {
+				entry.URL = fmt.Sprintf("%s/pull/%d", base, entry.PRID)
+			} else {
+				entry.URL = base + "/commit/" + c.SHA
+			}
+		}
+		data.Sections[section] = append(data.Sections[section], entry)
+	}
+	return data
+}
+
+// RenderTemplate executes the changelog writer template (Go text/template) with data.
+func RenderTemplate(tmpl string, data ChangelogTemplateData) (string, error) {
+	t, err := template.New("changelog").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse changelog template: %w", err)
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render changelog template: %w", err)
+	}
+	return b.String(), nil
+}
+
+func shortCommitSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
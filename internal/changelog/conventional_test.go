@@ -0,0 +1,77 @@
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/johnewart/releasebot/internal/git"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		subject  string
+		body     string
+		wantOK   bool
+		typ      string
+		scope    string
+		desc     string
+		breaking bool
+		prID     int
+		section  string
+	}{
+		{"feat: add widgets", "", true, "feat", "", "add widgets", false, 0, "Added"},
+		{"fix(api): handle nil token (#42)", "", true, "fix", "api", "handle nil token", false, 42, "Fixed"},
+		{"feat(cli)!: drop --legacy flag", "", true, "feat", "cli", "drop --legacy flag", true, 0, "Added"},
+		{"refactor: split parser", "Some context.\n\nBREAKING CHANGE: Parse now returns an error", true, "refactor", "", "split parser", true, 0, "Changed"},
+		{"Docs: typo", "", true, "docs", "", "typo", false, 0, "Docs"},
+		{"chore(deps): bump x", "", true, "chore", "deps", "bump x", false, 0, "Developer Experience"},
+		{"wip: something", "", true, "wip", "", "something", false, 0, "Changed"},
+		{"Update README", "", false, "", "", "", false, 0, ""},
+		{"feat:missing space", "", false, "", "", "", false, 0, ""},
+	}
+	for _, tt := range tests {
+		cc, ok := ParseConventionalCommit(tt.subject, tt.body)
+		if ok != tt.wantOK {
+			t.Errorf("ParseConventionalCommit(%q) ok = %v, want %v", tt.subject, ok, tt.wantOK)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if cc.Type != tt.typ || cc.Scope != tt.scope || cc.Description != tt.desc || cc.Breaking != tt.breaking || cc.PRID != tt.prID || cc.ChangeType() != tt.section {
+			t.Errorf("ParseConventionalCommit(%q) = %+v (section %s)", tt.subject, cc, cc.ChangeType())
+		}
+	}
+}
+
+func TestCommitTemplateData(t *testing.T) {
+	commits := []git.Commit{
+		{SHA: "aaaaaaa1111", Subject: "feat(api): add endpoint (#7)"},
+		{SHA: "bbbbbbb2222", Subject: "fix: crash on start"},
+		{SHA: "ccccccc3333", Subject: "Update README"},
+		{SHA: "ddddddd4444", Subject: "feat!: drop v1 API", Body: "BREAKING CHANGE: clients must use /v2"},
+	}
+	data := CommitTemplateData("v1.2.0", "https://github.com/o/r", commits)
+	if got := data.Sections["Added"]; len(got) != 2 || got[0].PRID != 7 || got[0].URL != "https://github.com/o/r/pull/7" || got[0].Description != "**api:** add endpoint" {
+		t.Errorf("Added = %+v", got)
+	} else if !got[1].Breaking || got[1].BreakingMsg != "clients must use /v2" {
+		t.Errorf("breaking entry = %+v, want BreakingMsg from the footer", got[1])
+	}
+	if got := data.Sections["Fixed"]; len(got) != 1 || got[0].SHA != "bbbbbbb" || got[0].URL != "https://github.com/o/r/commit/bbbbbbb2222" {
+		t.Errorf("Fixed = %+v", got)
+	}
+	if got := data.Sections["Changed"]; len(got) != 1 || got[0].Description != "Update README" {
+		t.Errorf("Changed = %+v", got)
+	}
+	out, err := RenderTemplate("{{range $s := .SectionOrder}}{{range index $.Sections $s}}{{$s}}: {{.Description}}\n{{end}}{{end}}", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Added: **api:** add endpoint\nAdded: drop v1 API\nChanged: Update README\nFixed: crash on start\n"
+	if out != want {
+		t.Errorf("RenderTemplate = %q, want %q", out, want)
+	}
+	if _, err := RenderTemplate("{{.Nope", data); err == nil || !strings.Contains(err.Error(), "parse changelog template") {
+		t.Errorf("expected parse error, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package changelog
+
+import "strings"
+
+// Entry is one bullet of a changelog section: lines[Start:End] of the section, the bullet line followed by
+// the indented lines under it (wrapped text, nested bullets).
+type Entry struct {
+	Start, End int
+}
+
+// Entries returns the top-level bullets ("- " or "* " at the start of a line) of a changelog section.
+func Entries(section string) []Entry {
+	lines := strings.Split(section, "\n")
+	var entries []Entry
+	for i := 0; i < len(lines); i++ {
+		if !isBullet(lines[i]) {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && lines[end] != "" && (lines[end][0] == ' ' || lines[end][0] == '\t') {
+			end++
+		}
+		entries = append(entries, Entry{Start: i, End: end})
+		i = end - 1
+	}
+	return entries
+}
+
+// DropEntry returns section without the entry e (from Entries). A subheading ("### Added") left with no
+// entries is dropped too; the version heading is kept.
+func DropEntry(section string, e Entry) string {
+	lines := strings.Split(section, "\n")
+	if e.Start < 0 || e.End > len(lines) || e.Start >= e.End {
+		return section
+	}
+	lines = append(lines[:e.Start:e.Start], lines[e.End:]...)
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "###") && subsectionEmpty(lines[i+1:]) {
+			// Skip the heading and the blank lines after it.
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" {
+				i++
+			}
+			continue
+		}
+		out = append(out, lines[i])
+	}
+	// Dropping the last entry of a list can leave two blank lines in a row.
+	var b strings.Builder
+	for i, l := range out {
+		if l == "" && i > 0 && out[i-1] == "" {
+			continue
+		}
+		b.WriteString(l)
+		if i < len(out)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimSpace(b.String()) + "\n"
+}
+
+// Prepend returns changelog content with section above existing (the changelog's previous contents), as
+// Generate writes it.
+func Prepend(section, existing string) string {
+	section = strings.TrimSpace(section) + "\n"
+	if existing == "" {
+		return section
+	}
+	return section + "\n" + existing
+}
+
+// subsectionEmpty returns true if lines (those after a heading) have no content before the next heading.
+func subsectionEmpty(lines []string) bool {
+	for _, l := range lines {
+		if strings.HasPrefix(l, "#") {
+			return true
+		}
+		if strings.TrimSpace(l) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func isBullet(line string) bool {
+	return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")
+}
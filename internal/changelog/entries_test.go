@@ -0,0 +1,52 @@
+package changelog
+
+import "testing"
+
+func TestEntries(t *testing.T) {
+	section := "## v1.2.0\n\n### Added\n\n- New thing\n  wrapped line\n- Other thing\n\n### Fixed\n\n* A fix\n"
+	got := Entries(section)
+	want := []Entry{{4, 6}, {6, 7}, {10, 11}}
+	if len(got) != len(want) {
+		t.Fatalf("Entries = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Entries[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDropEntry(t *testing.T) {
+	section := "## v1.2.0\n\n### Added\n\n- New thing\n  wrapped line\n- Other thing\n\n### Fixed\n\n- A fix\n"
+	tests := []struct {
+		name  string
+		entry int
+		want  string
+	}{
+		{"with continuation", 0, "## v1.2.0\n\n### Added\n\n- Other thing\n\n### Fixed\n\n- A fix\n"},
+		{"last of subsection", 2, "## v1.2.0\n\n### Added\n\n- New thing\n  wrapped line\n- Other thing\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DropEntry(section, Entries(section)[tt.entry]); got != tt.want {
+				t.Errorf("DropEntry = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Dropping the only entry of the first subsection removes its heading but keeps the version heading.
+	single := "## v1.2.0\n\n### Added\n\n- New thing\n\n### Fixed\n\n- A fix\n"
+	want := "## v1.2.0\n\n### Fixed\n\n- A fix\n"
+	if got := DropEntry(single, Entries(single)[0]); got != want {
+		t.Errorf("DropEntry = %q, want %q", got, want)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	if got, want := Prepend("## v1.1.0\n\n- New\n\n", "## v1.0.0\n\n- Old\n"), "## v1.1.0\n\n- New\n\n## v1.0.0\n\n- Old\n"; got != want {
+		t.Errorf("Prepend = %q, want %q", got, want)
+	}
+	if got, want := Prepend("## v1.0.0\n\n- First", ""), "## v1.0.0\n\n- First\n"; got != want {
+		t.Errorf("Prepend to empty = %q, want %q", got, want)
+	}
+}
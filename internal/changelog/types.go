@@ -67,10 +67,14 @@ func ParsePRChangeJSON(raw string, prID int) (*PRChange, error) {
 }
 
 // TemplateEntry is passed to the changelog template for each item (description + link).
+// Entries built from git history have SHA set (short commit hash) and PRID only when the subject references a PR.
 type TemplateEntry struct {
 	Description string
 	PRID        int
 	URL         string
+	SHA         string
+	Breaking    bool   // Conventional Commit marked as a breaking change
+	BreakingMsg string // text of the BREAKING CHANGE footer explaining the break, if any
 }
 
 // ChangelogTemplateData is the struct passed to the changelog writer template.
@@ -80,3 +84,40 @@ type ChangelogTemplateData struct {
 	Sections     map[string][]TemplateEntry // e.g. Sections["Added"], Sections["Fixed"]
 	SectionOrder []string                   // order to iterate sections (e.g. Added, Changed, ...)
 }
+
+// withPRURLs links the entries with a PR through prURLFormat (see GenerateOptions.PRURLFormat); the GitHub
+// links set from RepoURL are kept when prURLFormat is empty.
+func (d ChangelogTemplateData) withPRURLs(prURLFormat string) ChangelogTemplateData {
+	if prURLFormat == "" {
+		return d
+	}
+	for _, entries := range d.Sections {
+		for i := range entries {
+			if entries[i].PRID > 0 {
+				entries[i].URL = fmt.Sprintf(prURLFormat, entries[i].PRID)
+			}
+		}
+	}
+	return d
+}
+
+// PRTemplateData groups per-PR changes into ChangelogTemplateData sections by change type, in PR order.
+// repoURL (e.g. https://github.com/owner/repo) is used for the PR links when set.
+func PRTemplateData(version, repoURL string, changes []*PRChange) ChangelogTemplateData {
+	base := strings.TrimSuffix(repoURL, "/")
+	data := ChangelogTemplateData{
+		Version:      version,
+		RepoURL:      repoURL,
+		Sections:     make(map[string][]TemplateEntry),
+		SectionOrder: ValidChangeTypes,
+	}
+	for _, c := range changes {
+		entry := TemplateEntry{Description: c.Description, PRID: c.PRID}
+		if base != "" && c.PRID > 0 {
+			entry.URL = fmt.Sprintf("%s/pull/%d", base, c.PRID)
+		}
+		typ := NormalizeChangeType(c.ChangeType)
+		data.Sections[typ] = append(data.Sections[typ], entry)
+	}
+	return data
+}
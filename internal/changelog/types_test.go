@@ -0,0 +1,27 @@
+package changelog
+
+import "testing"
+
+func TestPRTemplateData(t *testing.T) {
+	changes := []*PRChange{
+		{ChangeType: "Fixed", Description: "Fix crash", PRID: 3},
+		{ChangeType: "Added", Description: "Add export", PRID: 1},
+		{ChangeType: "added", Description: "Add import", PRID: 2},
+	}
+	data := PRTemplateData("v2.0.0", "https://github.com/o/r/", changes)
+	tmpl := "## {{.Version}}\n{{range $s := .SectionOrder}}{{with index $.Sections $s}}### {{$s}}\n{{range .}}- {{.Description}} [#{{.PRID}}]({{.URL}})\n{{end}}{{end}}{{end}}"
+	out, err := RenderTemplate(tmpl, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## v2.0.0\n### Added\n- Add export [#1](https://github.com/o/r/pull/1)\n- Add import [#2](https://github.com/o/r/pull/2)\n### Fixed\n- Fix crash [#3](https://github.com/o/r/pull/3)\n"
+	if out != want {
+		t.Errorf("RenderTemplate = %q, want %q", out, want)
+	}
+
+	// A PR URL format (e.g. GitLab merge requests) replaces the GitHub links.
+	data = data.withPRURLs("https://gitlab.com/o/r/-/merge_requests/%d")
+	if got := data.Sections["Fixed"][0].URL; got != "https://gitlab.com/o/r/-/merge_requests/3" {
+		t.Errorf("withPRURLs URL = %q", got)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/johnewart/releasebot/internal/semver"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,14 +18,41 @@ type Config struct {
 	Changelog *ChangelogConfig `yaml:"changelog"`
 	// GitHub holds optional GitHub API settings for fetching PRs.
 	GitHub *GitHubConfig `yaml:"github"`
+	// GitLab holds optional GitLab API settings for merge requests, pipelines and releases.
+	GitLab *GitLabConfig `yaml:"gitlab"`
+	// Forge selects the code host API used for PRs, CI and releases: "github" or "gitlab". When empty it is
+	// gitlab if only gitlab is configured, else detected from the remote URL (hosts containing "gitlab").
+	Forge string `yaml:"forge"`
 	// LLM at top level (optional). Used when changelog.llm is not set. Lets you enable LLM with only "llm:" in config.
 	LLM *LLMConfig `yaml:"llm"`
 	// PreviousReleaseTag can be set in config (overridden by --prev-tag).
 	PreviousReleaseTag string `yaml:"previous_release_tag"`
+	// TagScheme is the version tag format: "pep440" (default; X.Y.ZrcN, X.Y.ZaN) or "semver2"
+	// (X.Y.Z-rc.N, X.Y.Z-beta.2+build.7). Used to find the previous release and compute next tags.
+	TagScheme string `yaml:"tag_scheme"`
 	// Release holds settings for the release command (remote, pypi, docker).
 	Release *ReleaseConfig `yaml:"release"`
 	// Slack holds optional Slack notification (e.g. when run completes).
 	Slack *SlackConfig `yaml:"slack"`
+	// PyPI holds named Python package indexes for the pypi step and command.
+	PyPI *PyPIConfig `yaml:"pypi"`
+	// Components configures monorepo components by name (selected with --component).
+	Components map[string]*ComponentConfig `yaml:"components"`
+
+	// component is the component selected with UseComponent (nil = whole repository).
+	component *ComponentConfig
+}
+
+// ComponentConfig configures one independently versioned component of a monorepo.
+type ComponentConfig struct {
+	// TagPrefix is prepended to the component's version tags (e.g. "api/" for api/v1.2.3, "worker-v" for worker-v0.9.1).
+	TagPrefix string `yaml:"tag_prefix"`
+	// Paths restricts changelog commits/PRs to those touching these paths (relative to the repo root).
+	Paths []string `yaml:"paths"`
+	// Changelog is the component's changelog output path (overrides changelog.output).
+	Changelog string `yaml:"changelog"`
+	// PreviousReleaseTag is the component's previous tag (replaces the top-level previous_release_tag).
+	PreviousReleaseTag string `yaml:"previous_release_tag"`
 }
 
 // SlackConfig configures Slack notifications (e.g. on run completion).
@@ -37,10 +66,170 @@ type SlackConfig struct {
 type ReleaseConfig struct {
 	// Remote is the git remote to push branch and tags to (default: origin).
 	Remote string `yaml:"remote"`
-	// PyPIPackage is the PyPI package name to check/wait for after release (e.g. my-package). If set, release waits for package==version on PyPI.
-	PyPIPackage string `yaml:"pypi_package"`
-	// DockerImage is the Docker image to check/wait for (e.g. myorg/myimage). If set, release waits for myorg/myimage:tag on Docker Hub.
-	DockerImage string `yaml:"docker_image"`
+	// PyPIPackage is the Python package to check/wait for after release, as a name (my-package) or a mapping
+	// with the index to wait on ({name: my-package, index: internal}). If set, release waits for package==version.
+	PyPIPackage PyPIPackage `yaml:"pypi_package"`
+	// PyPIFiles lists the distributions the released version must provide before the pypi step passes:
+	// sdist, wheel, or wheel tags such as cp311-manylinux_x86_64 (see pypi.VerifyOptions).
+	PyPIFiles []string `yaml:"pypi_files"`
+	// DockerImage is the container image(s) to check/wait for, as one name or a list (e.g. myorg/myimage,
+	// ghcr.io/org/app, registry.example.com:5000/team/app). If set, release waits for each image:tag in its registry.
+	DockerImage StringList `yaml:"docker_image"`
+	// ImagePlatforms lists platforms (os/arch[/variant], e.g. linux/amd64, linux/arm64) every released image must provide.
+	ImagePlatforms []string `yaml:"image_platforms"`
+	// ImageVersionLabel is an image config label (e.g. org.opencontainers.image.version) that must match the release version.
+	ImageVersionLabel string `yaml:"image_version_label"`
+	// NpmPackage is the npm package(s) to wait for after release (e.g. my-package, @scope/my-package).
+	NpmPackage StringList `yaml:"npm_package"`
+	// NpmRegistry is the npm registry URL (default https://registry.npmjs.org/).
+	NpmRegistry string `yaml:"npm_registry"`
+	// Crate is the crates.io crate(s) to wait for after release.
+	Crate StringList `yaml:"crate"`
+	// MavenArtifact is the Maven artifact(s) to wait for after release, as groupId:artifactId.
+	MavenArtifact StringList `yaml:"maven_artifact"`
+	// MavenRepository is the Maven repository URL (default Maven Central, https://repo1.maven.org/maven2/).
+	MavenRepository string `yaml:"maven_repository"`
+	// GoModule is the Go module path(s) to wait for on the module proxy after release (e.g. github.com/org/repo).
+	GoModule StringList `yaml:"go_module"`
+	// GoProxy is the Go module proxy URL (default https://proxy.golang.org/).
+	GoProxy string `yaml:"go_proxy"`
+	// WorkflowReruns is how many times the wait_workflows step re-runs the failed jobs of failed release
+	// workflows before failing the release (default 0: no re-runs).
+	WorkflowReruns int `yaml:"workflow_reruns"`
+	// GitHubRelease, when true, adds a github_release step after wait_workflows to the default pipeline.
+	GitHubRelease bool `yaml:"github_release"`
+	// Assets are file globs (relative to the repo root) uploaded to the GitHub Release.
+	Assets []string `yaml:"assets"`
+	// Steps is the release pipeline. When empty, the default pipeline is used:
+	// just, changelog, commit_tag, push, wait_workflows, [github_release,] pypi, dockerhub, followed by
+	// npm, crates, maven and goproxy for those with packages configured.
+	Steps []ReleaseStepConfig `yaml:"steps"`
+}
+
+// Release step types for release.steps.
+const (
+	StepJust          = "just"
+	StepChangelog     = "changelog"
+	StepCommitTag     = "commit_tag"
+	StepPush          = "push"
+	StepWaitWorkflows = "wait_workflows"
+	StepPyPI          = "pypi"
+	StepDockerHub     = "dockerhub"
+	StepShell         = "shell"
+	StepNotify        = "notify"
+	StepGitHubRelease = "github_release"
+	StepNpm           = "npm"
+	StepCrates        = "crates"
+	StepMaven         = "maven"
+	StepGoProxy       = "goproxy"
+)
+
+// DefaultReleaseSteps is the pipeline used when release.steps is not set.
+var DefaultReleaseSteps = []ReleaseStepConfig{
+	{Type: StepJust},
+	{Type: StepChangelog},
+	{Type: StepCommitTag},
+	{Type: StepPush},
+	{Type: StepWaitWorkflows},
+	{Type: StepPyPI},
+	{Type: StepDockerHub},
+}
+
+// ReleaseStepConfig is one entry in release.steps. Type selects the built-in step; the other
+// fields are options for that type and fall back to the top-level config when empty.
+type ReleaseStepConfig struct {
+	// Type is one of: just, changelog, commit_tag, push, wait_workflows, github_release, pypi, dockerhub,
+	// npm, crates, maven, goproxy, shell, notify.
+	Type string `yaml:"type"`
+	// Name is the display name in the TUI and state file (default depends on type).
+	Name string `yaml:"name"`
+	// Targets (just) overrides justfile.targets for this step.
+	Targets []string `yaml:"targets"`
+	// WorkingDir (just, shell) is the directory to run in, relative to the repo root.
+	WorkingDir string `yaml:"working_dir"`
+	// Run (shell) is the command to run with sh -c. RELEASEBOT_TAG, RELEASEBOT_PREV_TAG,
+	// RELEASEBOT_BRANCH and RELEASEBOT_REMOTE are set in its environment.
+	Run string `yaml:"run"`
+	// Package (pypi, npm, crates, maven, goproxy) overrides the package configured for the step's registry
+	// (release.pypi_package, npm_package, crate, maven_artifact, go_module).
+	Package string `yaml:"package"`
+	// Index (pypi) overrides the index of release.pypi_package: a name from pypi.indexes, pypi, testpypi, or a URL.
+	Index string `yaml:"index"`
+	// Files (pypi) overrides release.pypi_files.
+	Files []string `yaml:"files"`
+	// URL (npm, crates, maven, goproxy) overrides the registry URL (release.npm_registry, maven_repository, go_proxy).
+	URL string `yaml:"url"`
+	// Image (dockerhub) overrides release.docker_image (one image or a list).
+	Image StringList `yaml:"image"`
+	// Platforms (dockerhub) overrides release.image_platforms.
+	Platforms []string `yaml:"platforms"`
+	// VersionLabel (dockerhub) overrides release.image_version_label.
+	VersionLabel string `yaml:"version_label"`
+	// Timeout (wait_workflows, pypi, dockerhub, npm, crates, maven, goproxy, shell) overrides the command-line timeout (e.g. 15m).
+	Timeout time.Duration `yaml:"timeout"`
+	// Reruns (wait_workflows) overrides release.workflow_reruns.
+	Reruns int `yaml:"reruns"`
+	// Message (notify) is a Go text/template with .Tag, .PrevTag, .Branch, .Remote (default "Released {{.Tag}}").
+	Message string `yaml:"message"`
+	// Assets (github_release) overrides release.assets.
+	Assets []string `yaml:"assets"`
+}
+
+// StringList is a list of strings that can also be written as a single YAML string.
+type StringList []string
+
+// UnmarshalYAML accepts a scalar ("a") or a sequence ([a, b]).
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		if value.Value == "" {
+			*l = nil
+		} else {
+			*l = StringList{value.Value}
+		}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// PyPIPackage is a Python package name and the index it is published to.
+type PyPIPackage struct {
+	// Name is the package name (e.g. my-package).
+	Name string `yaml:"name"`
+	// Index is a name from pypi.indexes, "pypi" (default), "testpypi", or an index URL.
+	Index string `yaml:"index"`
+}
+
+// UnmarshalYAML accepts a package name ("my-package") or a mapping ({name: my-package, index: internal}).
+func (p *PyPIPackage) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*p = PyPIPackage{Name: value.Value}
+		return nil
+	}
+	type plain PyPIPackage
+	return value.Decode((*plain)(p))
+}
+
+// PyPIConfig configures Python package indexes.
+type PyPIConfig struct {
+	// Indexes maps index names (used by release.pypi_package, the pypi step's index and pypi --index) to indexes.
+	Indexes map[string]*PyPIIndexConfig `yaml:"indexes"`
+}
+
+// PyPIIndexConfig is a private or alternate Python package index.
+// Username, Password and Token are expanded with environment variables (e.g. ${ARTIFACTORY_TOKEN}).
+type PyPIIndexConfig struct {
+	// URL is the simple index URL as given to pip --index-url (e.g. https://pypi.example.com/simple/).
+	URL string `yaml:"url"`
+	// Username and Password are sent with basic auth when Username is set.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// Token is sent as a bearer token when set and Username is empty.
+	Token string `yaml:"token"`
 }
 
 // JustfileConfig configures execution of justfile recipes.
@@ -63,8 +252,9 @@ type ChangelogConfig struct {
 	Format string `yaml:"format"`
 	// FormatFile path to a file containing the format template (overrides Format if set).
 	FormatFile string `yaml:"format_file"`
-	// Template is the changelog writer template (Go text/template) when using summarize_per_pr.
-	// Sections are in .Sections (e.g. .Sections.Added). Each entry has .Description, .PRID, .URL.
+	// Template is the changelog writer template (Go text/template) when using summarize_per_pr, and for
+	// git history grouped by Conventional Commit type when no LLM and no Format is configured.
+	// Sections are in .Sections (e.g. .Sections.Added). Each entry has .Description, .PRID, .URL, .SHA, .Breaking, .BreakingMsg.
 	// Can be multiline YAML (use | or >).
 	Template string `yaml:"template"`
 	// TemplateFile path to a file containing the changelog template (overrides Template if set).
@@ -88,6 +278,13 @@ type LLMConfig struct {
 	IncludeDiff bool `yaml:"include_diff"`
 	// CacheLLMSummaries: when SummarizePerPR is true, cache each PR's LLM summary (default true).
 	CacheLLMSummaries *bool `yaml:"cache_llm_summaries"`
+	// RenderTemplate: when SummarizePerPR is true, render changelog.template locally (Go text/template) from the
+	// per-PR records instead of sending it to the LLM for a final call. Output formatting is then exact.
+	RenderTemplate bool `yaml:"render_template"`
+	// Concurrency is how many PRs are summarized (and, with include_diff, diffs fetched) in parallel (default 4).
+	Concurrency int `yaml:"concurrency"`
+	// RequestsPerMinute caps per-PR LLM requests per minute for this provider (default 0 = unlimited).
+	RequestsPerMinute int `yaml:"requests_per_minute"`
 }
 
 // GitHubConfig configures optional GitHub API usage.
@@ -99,6 +296,41 @@ type GitHubConfig struct {
 	// Owner and Repo override git remote; if empty, derived from origin.
 	Owner string `yaml:"owner"`
 	Repo  string `yaml:"repo"`
+	// BaseURL is the API URL of a GitHub Enterprise Server instance (e.g. https://github.example.com/api/v3/);
+	// empty means github.com. Remotes on its host are recognized as GitHub remotes.
+	BaseURL string `yaml:"base_url"`
+	// UploadURL is the instance's upload API for release assets (default: BaseURL's host).
+	UploadURL string `yaml:"upload_url"`
+	// App authenticates as a GitHub App installation instead of with Token (see GitHubAppConfig).
+	App *GitHubAppConfig `yaml:"app"`
+}
+
+// GitHubAppConfig configures GitHub App authentication: releasebot mints installation access tokens from
+// the app's private key, so actions are attributed to the app rather than a person's token.
+type GitHubAppConfig struct {
+	// AppID is the app's ID (GITHUB_APP_ID when empty).
+	AppID int64 `yaml:"app_id"`
+	// InstallationID is the app's installation on the repo's owner (GITHUB_APP_INSTALLATION_ID when empty).
+	InstallationID int64 `yaml:"installation_id"`
+	// PrivateKeyPath is the app's PEM private key, relative to the repo root (GITHUB_APP_PRIVATE_KEY_PATH when empty).
+	PrivateKeyPath string `yaml:"private_key_path"`
+}
+
+// GitLabConfig configures optional GitLab API usage.
+type GitLabConfig struct {
+	// Enabled turns on fetching merged merge requests between tags (default: false).
+	Enabled bool `yaml:"enabled"`
+	// Token is a GitLab access token (prefer GITLAB_TOKEN env; set here for override).
+	Token string `yaml:"token"`
+	// URL is the GitLab instance (default: https:// plus the remote's host, e.g. https://gitlab.example.com).
+	URL string `yaml:"url"`
+	// Project is the project path with its namespace (e.g. group/subgroup/project); if empty, derived from the remote.
+	Project string `yaml:"project"`
+}
+
+// PRsEnabled returns true if fetching merged PRs (GitHub) or merge requests (GitLab) is turned on.
+func (c *Config) PRsEnabled() bool {
+	return (c.GitHub != nil && c.GitHub.Enabled) || (c.GitLab != nil && c.GitLab.Enabled)
 }
 
 // Load reads config from path. If the file does not exist, returns default config and nil error.
@@ -125,6 +357,91 @@ func (c *Config) Resolve(repoRoot string) {
 	if c.Justfile != nil && c.Justfile.WorkingDir != "" && !filepath.IsAbs(c.Justfile.WorkingDir) {
 		c.Justfile.WorkingDir = filepath.Join(repoRoot, c.Justfile.WorkingDir)
 	}
+	if c.GitHub != nil && c.GitHub.App != nil && c.GitHub.App.PrivateKeyPath != "" && !filepath.IsAbs(c.GitHub.App.PrivateKeyPath) {
+		c.GitHub.App.PrivateKeyPath = filepath.Join(repoRoot, c.GitHub.App.PrivateKeyPath)
+	}
+	if c.Release != nil {
+		for i := range c.Release.Steps {
+			if d := c.Release.Steps[i].WorkingDir; d != "" && !filepath.IsAbs(d) {
+				c.Release.Steps[i].WorkingDir = filepath.Join(repoRoot, d)
+			}
+		}
+	}
+}
+
+// ReleaseSteps returns the configured release pipeline, or DefaultReleaseSteps when release.steps is empty
+// (with a github_release step after wait_workflows when release.github_release is set, and npm, crates,
+// maven and goproxy steps at the end for the registries with packages configured).
+func (c *Config) ReleaseSteps() []ReleaseStepConfig {
+	if c.Release == nil {
+		return DefaultReleaseSteps
+	}
+	if len(c.Release.Steps) > 0 {
+		return c.Release.Steps
+	}
+	var steps []ReleaseStepConfig
+	for _, sc := range DefaultReleaseSteps {
+		steps = append(steps, sc)
+		if sc.Type == StepWaitWorkflows && c.Release.GitHubRelease {
+			steps = append(steps, ReleaseStepConfig{Type: StepGitHubRelease})
+		}
+	}
+	for _, a := range []struct {
+		step     string
+		packages StringList
+	}{
+		{StepNpm, c.Release.NpmPackage},
+		{StepCrates, c.Release.Crate},
+		{StepMaven, c.Release.MavenArtifact},
+		{StepGoProxy, c.Release.GoModule},
+	} {
+		if len(a.packages) > 0 {
+			steps = append(steps, ReleaseStepConfig{Type: a.step})
+		}
+	}
+	return steps
+}
+
+// UseComponent selects the named component: its previous tag and changelog output replace the
+// top-level settings, and TagPrefix and ComponentPaths return its values. Empty name selects nothing.
+func (c *Config) UseComponent(name string) error {
+	if name == "" {
+		return nil
+	}
+	comp, ok := c.Components[name]
+	if !ok || comp == nil {
+		return fmt.Errorf("unknown component %q (not in components config)", name)
+	}
+	c.component = comp
+	c.PreviousReleaseTag = comp.PreviousReleaseTag
+	if comp.Changelog != "" {
+		if c.Changelog == nil {
+			c.Changelog = &ChangelogConfig{}
+		}
+		c.Changelog.Output = comp.Changelog
+	}
+	return nil
+}
+
+// TagPrefix returns the selected component's tag prefix ("" when no component is selected).
+func (c *Config) TagPrefix() string {
+	if c.component == nil {
+		return ""
+	}
+	return c.component.TagPrefix
+}
+
+// ComponentPaths returns the selected component's paths (nil when no component is selected).
+func (c *Config) ComponentPaths() []string {
+	if c.component == nil {
+		return nil
+	}
+	return c.component.Paths
+}
+
+// Scheme returns the configured tag scheme (semver.SchemePEP440 when tag_scheme is not set).
+func (c *Config) Scheme() (semver.Scheme, error) {
+	return semver.ParseScheme(c.TagScheme)
 }
 
 // ChangelogFormat returns the changelog entry format string (from Format or FormatFile).
@@ -149,7 +466,12 @@ func (c *Config) ChangelogFormat(repoRoot string) (string, error) {
 	return "- {{.Title}} (#{{.Number}})", nil
 }
 
-// ChangelogTemplate returns the changelog writer template (for summarize_per_pr). Uses TemplateFile, then Template, then default.
+// HasChangelogFormat returns true if changelog.format or changelog.format_file is set.
+func (c *Config) HasChangelogFormat() bool {
+	return c.Changelog != nil && (c.Changelog.Format != "" || c.Changelog.FormatFile != "")
+}
+
+// ChangelogTemplate returns the changelog writer template (for summarize_per_pr, and for grouping git history without an LLM). Uses TemplateFile, then Template, then default.
 func (c *Config) ChangelogTemplate(repoRoot string) (string, error) {
 	if c.Changelog == nil {
 		return defaultChangelogTemplate, nil
@@ -173,10 +495,9 @@ func (c *Config) ChangelogTemplate(repoRoot string) (string, error) {
 
 // defaultChangelogTemplate is used as the structure/format passed to the LLM when generating the changelog from summarized records (describe version heading, sections by change type, and entry format).
 const defaultChangelogTemplate = `## {{.Version}}
-{{range $section := .SectionOrder}}{{if index $.Sections $section}}
+{{range $section := .SectionOrder}}{{with index $.Sections $section}}
 ### {{$section}}
-{{range index $.Sections $section}}
-- {{.Description}} [#{{.PRID}}]({{.URL}})
-{{end}}
-{{end}}{{end}}
-`
+
+{{range .}}- {{if .Breaking}}**BREAKING:** {{end}}{{.Description}}{{if .PRID}} [#{{.PRID}}]({{.URL}}){{else if .SHA}} ({{.SHA}}){{end}}
+{{if .BreakingMsg}}  {{.BreakingMsg}}
+{{end}}{{end}}{{end}}{{end}}`
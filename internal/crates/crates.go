@@ -0,0 +1,85 @@
+package crates
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// DefaultAPIURL is the crates.io API base URL.
+const DefaultAPIURL = "https://crates.io/api/v1/"
+
+// userAgent identifies releasebot; crates.io rejects API requests without a User-Agent.
+const userAgent = "releasebot (https://github.com/johnewart/releasebot)"
+
+// Client checks crates on crates.io (or a registry implementing its API).
+type Client struct {
+	// APIURL is the API base URL (default DefaultAPIURL).
+	APIURL string
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+var _ artifact.Checker = (*Client)(nil)
+
+// NewClient returns a client for apiURL (DefaultAPIURL when empty).
+func NewClient(apiURL string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{APIURL: apiURL, HTTPClient: http.DefaultClient}
+}
+
+// Check returns true if the crate exists on crates.io. If version is non-empty, returns true only when that
+// specific version is published. Returns false and nil on 404.
+func Check(ctx context.Context, name, version string) (bool, error) {
+	return NewClient("").Check(ctx, name, version)
+}
+
+// Registry returns the API host.
+func (c *Client) Registry() string {
+	return artifact.Host(c.APIURL)
+}
+
+// Check returns true if the crate exists (see the package-level Check), using GET /crates/<name>[/<version>].
+func (c *Client) Check(ctx context.Context, name, version string) (bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false, fmt.Errorf("crate name is required")
+	}
+	base, err := url.Parse(c.APIURL)
+	if err != nil {
+		return false, fmt.Errorf("crates api url: %w", err)
+	}
+	u := base.JoinPath("crates", name)
+	if version = strings.TrimPrefix(strings.TrimSpace(version), "v"); version != "" {
+		u = u.JoinPath(version)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, &artifact.StatusError{Host: c.Registry(), StatusCode: resp.StatusCode, Resource: u.String()}
+	}
+}
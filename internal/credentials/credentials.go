@@ -0,0 +1,147 @@
+// Package credentials finds API tokens outside releasebot's config: environment variables, the gh CLI, git
+// credential helpers and netrc.
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Token is a credential and where it was found (e.g. "GITHUB_TOKEN", "gh auth token").
+type Token struct {
+	Value  string
+	Source string
+}
+
+// lookupTimeout bounds each external command (gh, git credential) so a misbehaving helper cannot hang a release.
+const lookupTimeout = 10 * time.Second
+
+// Finder looks up tokens for a host. The zero value uses the process environment, the gh and git binaries on
+// PATH, and $NETRC or ~/.netrc.
+type Finder struct {
+	// Getenv reads environment variables (default os.Getenv).
+	Getenv func(string) string
+	// Run runs a command with stdin and returns its stdout (default: exec with a timeout and no prompts).
+	Run func(ctx context.Context, stdin string, name string, args ...string) (string, error)
+	// NetrcPath is the netrc file (default $NETRC, else ~/.netrc).
+	NetrcPath string
+}
+
+// Find returns the first token found for host, trying in order: the environment variables envNames,
+// `gh auth token --hostname host`, `git credential fill` for https://host, and the netrc machine entry for
+// host or api.host (never the default entry). ok is false when none has a token.
+func (f *Finder) Find(ctx context.Context, host string, envNames ...string) (tok Token, ok bool) {
+	for _, name := range envNames {
+		if v := f.getenv(name); v != "" {
+			return Token{Value: v, Source: name}, true
+		}
+	}
+	if out, err := f.run(ctx, "", "gh", "auth", "token", "--hostname", host); err == nil {
+		if v := strings.TrimSpace(out); v != "" {
+			return Token{Value: v, Source: "gh auth token"}, true
+		}
+	}
+	if out, err := f.run(ctx, "protocol=https\nhost="+host+"\n\n", "git", "credential", "fill"); err == nil {
+		if v := credentialField(out, "password"); v != "" {
+			return Token{Value: v, Source: "git credential helper"}, true
+		}
+	}
+	path := f.netrcPath()
+	if data, err := os.ReadFile(path); err == nil {
+		if v := NetrcPassword(data, host, "api."+host); v != "" {
+			return Token{Value: v, Source: path}, true
+		}
+	}
+	return Token{}, false
+}
+
+func (f *Finder) getenv(name string) string {
+	if f.Getenv != nil {
+		return f.Getenv(name)
+	}
+	return os.Getenv(name)
+}
+
+func (f *Finder) run(ctx context.Context, stdin, name string, args ...string) (string, error) {
+	if f.Run != nil {
+		return f.Run(ctx, stdin, name, args...)
+	}
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	// Never prompt: a missing credential must not block on a terminal or askpass dialog.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=true", "GCM_INTERACTIVE=never", "GH_PROMPT_DISABLED=1")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+func (f *Finder) netrcPath() string {
+	if f.NetrcPath != "" {
+		return f.NetrcPath
+	}
+	if p := f.getenv("NETRC"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// credentialField returns the value of key in `git credential` output (key=value lines).
+func credentialField(out, key string) string {
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(sc.Text(), "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// NetrcPassword returns the password of the first netrc machine entry for one of hosts (in the order given),
+// else "". The default entry is never used: its password belongs to some other host and must not be sent as a
+// token. Entries after a macdef are not read.
+func NetrcPassword(data []byte, hosts ...string) string {
+	passwords := make(map[string]string)
+	machine := ""
+	fields := strings.Fields(string(data))
+loop:
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine":
+			if i+1 < len(fields) {
+				i++
+				machine = fields[i]
+			}
+		case "default":
+			machine = ""
+		case "password":
+			if i+1 >= len(fields) {
+				break loop
+			}
+			i++
+			if _, seen := passwords[machine]; machine != "" && !seen {
+				passwords[machine] = fields[i]
+			}
+		case "macdef":
+			break loop
+		}
+	}
+	for _, h := range hosts {
+		if p := passwords[h]; p != "" {
+			return p
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNetrcPassword(t *testing.T) {
+	data := []byte(`machine example.com login a password one
+machine api.github.com
+  login bot
+  password two
+default login anon password three
+`)
+	tests := []struct {
+		hosts []string
+		want  string
+	}{
+		{[]string{"example.com"}, "one"},
+		{[]string{"github.com", "api.github.com"}, "two"},
+		// The default entry's password belongs to some other host and is never returned.
+		{[]string{"other.com"}, ""},
+	}
+	for _, tt := range tests {
+		if got := NetrcPassword(data, tt.hosts...); got != tt.want {
+			t.Errorf("NetrcPassword(%v) = %q, want %q", tt.hosts, got, tt.want)
+		}
+	}
+	if got := NetrcPassword([]byte("machine a password x"), "b"); got != "" {
+		t.Errorf("no match = %q, want empty", got)
+	}
+}
+
+func TestFinderFind(t *testing.T) {
+	netrc := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(netrc, []byte("machine api.github.com password from-netrc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{}
+	outputs := map[string]string{}
+	f := &Finder{
+		Getenv:    func(k string) string { return env[k] },
+		NetrcPath: netrc,
+		Run: func(ctx context.Context, stdin, name string, args ...string) (string, error) {
+			if out, ok := outputs[name]; ok {
+				return out, nil
+			}
+			return "", errors.New("not found")
+		},
+	}
+	ctx := context.Background()
+	steps := []struct {
+		setup func()
+		want  Token
+	}{
+		{func() {}, Token{"from-netrc", netrc}},
+		{func() { outputs["git"] = "protocol=https\nhost=github.com\nusername=x\npassword=from-git\n" }, Token{"from-git", "git credential helper"}},
+		{func() { outputs["gh"] = "from-gh\n" }, Token{"from-gh", "gh auth token"}},
+		{func() { env["GITHUB_TOKEN"] = "from-env" }, Token{"from-env", "GITHUB_TOKEN"}},
+	}
+	for _, s := range steps {
+		s.setup()
+		if got, ok := f.Find(ctx, "github.com", "GITHUB_TOKEN"); !ok || got != s.want {
+			t.Errorf("Find = %+v (%v), want %+v", got, ok, s.want)
+		}
+	}
+	if _, ok := (&Finder{Getenv: func(string) string { return "" }, NetrcPath: filepath.Join(t.TempDir(), "none"),
+		Run: func(context.Context, string, string, ...string) (string, error) { return "", errors.New("no") }}).Find(ctx, "github.com"); ok {
+		t.Error("expected no token")
+	}
+}
@@ -0,0 +1,61 @@
+// Package forge selects the code host (GitHub or GitLab) a repository's changelog, CI and releases go through.
+package forge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/git"
+	"github.com/johnewart/releasebot/internal/github"
+	"github.com/johnewart/releasebot/internal/gitlab"
+)
+
+// Supported forges.
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+)
+
+// ChangeSource finds the merged pull requests (GitHub) or merge requests (GitLab) for the changelog.
+type ChangeSource interface {
+	// MergedPRsBetweenWithProgress returns the merged requests of the commits in base..head.
+	MergedPRsBetweenWithProgress(ctx context.Context, base, head string, report func(string), reportProgress func(current, total int)) ([]github.PullRequest, error)
+	// MergedPRsForCommits returns the merged requests containing the given commits, deduplicated in commit order.
+	MergedPRsForCommits(ctx context.Context, shas []string, report func(string), reportProgress func(current, total int)) ([]github.PullRequest, error)
+	// GetPRDiff returns the unified diff of a request.
+	GetPRDiff(ctx context.Context, number int) (string, error)
+}
+
+var (
+	_ ChangeSource = (*github.Client)(nil)
+	_ ChangeSource = (*gitlab.Client)(nil)
+)
+
+// Normalize validates a configured forge name; "" is returned unchanged (detect from the remote).
+func Normalize(name string) (string, error) {
+	switch n := strings.ToLower(strings.TrimSpace(name)); n {
+	case "", GitHub, GitLab:
+		return n, nil
+	}
+	return "", fmt.Errorf("unknown forge %q (want github or gitlab)", name)
+}
+
+// Detect returns the forge hosting remoteURL: GitLab when the host is gitlab.com or its name contains "gitlab"
+// (e.g. gitlab.example.com), else GitHub.
+func Detect(remoteURL string) string {
+	host, _, err := git.ParseRemoteHostPath(remoteURL)
+	if err == nil && strings.Contains(strings.ToLower(host), "gitlab") {
+		return GitLab
+	}
+	return GitHub
+}
+
+// PRURLFormat returns the fmt format of a request's web URL (one %d for its number) in the project at webURL.
+func PRURLFormat(kind, webURL string) string {
+	base := strings.ReplaceAll(strings.TrimSuffix(webURL, "/"), "%", "%%")
+	if kind == GitLab {
+		return base + "/-/merge_requests/%d"
+	}
+	return base + "/pull/%d"
+}
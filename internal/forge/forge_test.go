@@ -0,0 +1,31 @@
+package forge
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"https://github.com/o/r.git", GitHub},
+		{"git@github.com:o/r.git", GitHub},
+		{"https://gitlab.com/group/sub/proj.git", GitLab},
+		{"git@gitlab.example.com:group/proj.git", GitLab},
+		{"ssh://git@gitlab.internal:2222/group/proj.git", GitLab},
+		{"not a url", GitHub},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.remote); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestPRURLFormat(t *testing.T) {
+	if got := PRURLFormat(GitLab, "https://gitlab.com/g/p/"); got != "https://gitlab.com/g/p/-/merge_requests/%d" {
+		t.Errorf("gitlab format = %q", got)
+	}
+	if got := PRURLFormat(GitHub, "https://github.com/o/r"); got != "https://github.com/o/r/pull/%d" {
+		t.Errorf("github format = %q", got)
+	}
+}
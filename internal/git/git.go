@@ -36,19 +36,26 @@ func RevParse(ctx context.Context, repoPath, ref string) (string, error) {
 }
 
 // LogBetween returns commit messages (one per line, format: hash subject) between base and head (exclusive of base).
-func LogBetween(ctx context.Context, repoPath, baseRef, headRef string) ([]Commit, error) {
+// When paths are given, only commits touching those paths are returned.
+func LogBetween(ctx context.Context, repoPath, baseRef, headRef string, paths ...string) ([]Commit, error) {
 	head := headRef
 	if head == "" {
 		head = "HEAD"
 	}
-	cmd := exec.CommandContext(ctx, "git", "log", "--format=%H%x00%s%x00%b%x00", baseRef+".."+head)
+	// Records end with a record separator (0x1e): bodies span several lines, so newlines cannot delimit commits.
+	args := []string{"log", "--format=%H%x00%s%x00%b%x1e", baseRef + ".." + head}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
 	cmd.Dir = repoPath
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("git log: %w", err)
 	}
 	var commits []Commit
-	for _, block := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+	for _, block := range strings.Split(string(out), "\x1e") {
+		block = strings.TrimLeft(block, "\n")
 		if block == "" {
 			continue
 		}
@@ -69,6 +76,26 @@ func LogBetween(ctx context.Context, repoPath, baseRef, headRef string) ([]Commi
 	return commits, nil
 }
 
+// CommitSHAsBetween returns the SHAs of commits between base and head (exclusive of base) touching any of paths
+// (all commits in the range when paths is empty).
+func CommitSHAsBetween(ctx context.Context, repoPath, baseRef, headRef string, paths ...string) ([]string, error) {
+	head := headRef
+	if head == "" {
+		head = "HEAD"
+	}
+	args := []string{"log", "--format=%H", baseRef + ".." + head}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log: %w", err)
+	}
+	return strings.Fields(string(out)), nil
+}
+
 // Commit represents a git commit for changelog input.
 type Commit struct {
 	SHA     string
@@ -187,18 +214,119 @@ func Push(ctx context.Context, repoPath, remote, ref string) error {
 // ParseGitHubOwnerRepo extracts owner and repo from a git remote URL.
 // Supports https://github.com/owner/repo[.git] and git@github.com:owner/repo[.git].
 func ParseGitHubOwnerRepo(remoteURL string) (owner, repo string, err error) {
-	remoteURL = strings.TrimSuffix(remoteURL, ".git")
-	var rest string
-	if strings.HasPrefix(remoteURL, "https://github.com/") {
-		rest = strings.TrimPrefix(remoteURL, "https://github.com/")
-	} else if strings.HasPrefix(remoteURL, "git@github.com:") {
-		rest = strings.TrimPrefix(remoteURL, "git@github.com:")
-	} else {
-		return "", "", fmt.Errorf("not a GitHub URL: %s", remoteURL)
-	}
-	parts := strings.SplitN(rest, "/", 2)
-	if len(parts) != 2 {
+	return ParseGitHubOwnerRepoOnHost(remoteURL, "github.com")
+}
+
+// ParseGitHubOwnerRepoOnHost extracts owner and repo from a git remote URL on host (e.g. a GitHub Enterprise
+// Server host), in any form ParseRemoteHostPath accepts. Ports are ignored when comparing hosts.
+func ParseGitHubOwnerRepoOnHost(remoteURL, host string) (owner, repo string, err error) {
+	remoteHost, path, err := ParseRemoteHostPath(remoteURL)
+	if err != nil || !strings.EqualFold(hostWithoutPort(remoteHost), hostWithoutPort(host)) {
+		return "", "", fmt.Errorf("not a GitHub URL for %s: %s", host, remoteURL)
+	}
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return "", "", fmt.Errorf("invalid GitHub URL: %s", remoteURL)
 	}
 	return parts[0], parts[1], nil
 }
+
+// hostWithoutPort returns host without a trailing :port.
+func hostWithoutPort(host string) string {
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		return host[:i]
+	}
+	return host
+}
+
+// ParseRemoteHostPath splits a git remote URL into its host and repository path (without .git), for any
+// host: https://host/group/repo, ssh://git@host[:port]/group/repo and git@host:group/repo.
+func ParseRemoteHostPath(remoteURL string) (host, path string, err error) {
+	remoteURL = strings.TrimSuffix(strings.TrimSpace(remoteURL), ".git")
+	if i := strings.Index(remoteURL, "://"); i >= 0 {
+		rest := remoteURL[i+3:]
+		if at := strings.LastIndex(rest, "@"); at >= 0 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:]
+		}
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			return "", "", fmt.Errorf("invalid remote URL: %s", remoteURL)
+		}
+		host, path = rest[:slash], strings.Trim(rest[slash+1:], "/")
+		if remoteURL[:i] != "http" && remoteURL[:i] != "https" {
+			// ssh://git@host:2222/group/repo: the port is not part of the web host.
+			if colon := strings.Index(host, ":"); colon >= 0 {
+				host = host[:colon]
+			}
+		}
+	} else if colon := strings.Index(remoteURL, ":"); colon > 0 {
+		// scp-like syntax: [user@]host:group/repo
+		host, path = remoteURL[:colon], strings.Trim(remoteURL[colon+1:], "/")
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	}
+	if host == "" || !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("invalid remote URL: %s", remoteURL)
+	}
+	return host, path, nil
+}
+
+// DeleteTag deletes a local tag.
+func DeleteTag(ctx context.Context, repoPath, tag string) error {
+	cmd := exec.CommandContext(ctx, "git", "tag", "-d", tag)
+	cmd.Dir = repoPath
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git tag -d %s: %w (%s)", tag, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DeleteRemoteRef deletes a ref (e.g. refs/tags/v1.0.0) on the remote.
+func DeleteRemoteRef(ctx context.Context, repoPath, remote, ref string) error {
+	cmd := exec.CommandContext(ctx, "git", "push", remote, ":"+ref)
+	cmd.Dir = repoPath
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git push %s :%s: %w (%s)", remote, ref, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoteRefSHA returns the SHA the remote has for ref (e.g. refs/heads/main), or "" if the remote does not have it.
+// For annotated tags this is the tag object SHA.
+func RemoteRefSHA(ctx context.Context, repoPath, remote, ref string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", remote, ref)
+	cmd.Dir = repoPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-remote %s %s: %w", remote, ref, err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == ref {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
+
+// ForcePushWithLease updates branch on the remote to sha, but only if the remote branch is still at expectSHA.
+func ForcePushWithLease(ctx context.Context, repoPath, remote, branch, sha, expectSHA string) error {
+	ref := "refs/heads/" + branch
+	cmd := exec.CommandContext(ctx, "git", "push", "--force-with-lease="+ref+":"+expectSHA, remote, sha+":"+ref)
+	cmd.Dir = repoPath
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git push --force-with-lease %s %s: %w (%s)", remote, ref, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ResetMixed resets the current branch to ref, keeping working tree changes (git reset --mixed).
+func ResetMixed(ctx context.Context, repoPath, ref string) error {
+	cmd := exec.CommandContext(ctx, "git", "reset", "--mixed", ref)
+	cmd.Dir = repoPath
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset %s: %w (%s)", ref, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
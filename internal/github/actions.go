@@ -92,14 +92,9 @@ func AllRunsFinished(runs []*WorkflowRun) bool {
 	return true
 }
 
-// AnyRunFailed returns true if any run completed with a non-success conclusion.
+// AnyRunFailed returns true if any run completed with a failing conclusion.
 func AnyRunFailed(runs []*WorkflowRun) bool {
-	for _, r := range runs {
-		if r.GetStatus() == "completed" && r.GetConclusion() != "success" && r.GetConclusion() != "" {
-			return true
-		}
-	}
-	return false
+	return len(FailedRuns(runs)) > 0
 }
 
 // RunsForTagPushWorkflows filters runs to those matching the given tag-push workflow triggers (by workflow name).
@@ -120,3 +115,35 @@ func RunsForTagPushWorkflows(runs []*WorkflowRun, triggers []*WorkflowTrigger) [
 	}
 	return out
 }
+
+// FailedRuns returns the runs that completed with a failing conclusion (see AnyRunFailed). Skipped and
+// neutral runs (e.g. a workflow_run workflow whose jobs' conditions were not met) are not failures.
+func FailedRuns(runs []*WorkflowRun) []*WorkflowRun {
+	var out []*WorkflowRun
+	for _, r := range runs {
+		switch r.GetConclusion() {
+		case "", "success", "skipped", "neutral":
+			continue
+		}
+		if r.GetStatus() == "completed" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// RerunFailedJobs re-runs the failed jobs of a run (and the jobs that depend on them) as a new attempt.
+func (c *Client) RerunFailedJobs(ctx context.Context, runID int64) error {
+	if _, err := c.Actions.RerunFailedJobsByID(ctx, c.Owner, c.Repo, runID); err != nil {
+		return fmt.Errorf("re-run failed jobs of run %d: %w", runID, err)
+	}
+	return nil
+}
+
+// RerunWorkflow re-runs every job of a run as a new attempt.
+func (c *Client) RerunWorkflow(ctx context.Context, runID int64) error {
+	if _, err := c.Actions.RerunWorkflowByID(ctx, c.Owner, c.Repo, runID); err != nil {
+		return fmt.Errorf("re-run run %d: %w", runID, err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gh "github.com/google/go-github/v60/github"
+)
+
+func TestFailedRuns(t *testing.T) {
+	runs := []*WorkflowRun{
+		{ID: gh.Int64(1), Status: gh.String("completed"), Conclusion: gh.String("success")},
+		{ID: gh.Int64(2), Status: gh.String("completed"), Conclusion: gh.String("failure")},
+		{ID: gh.Int64(3), Status: gh.String("in_progress")},
+		{ID: gh.Int64(4), Status: gh.String("completed"), Conclusion: gh.String("cancelled")},
+	}
+	failed := FailedRuns(runs)
+	if len(failed) != 2 || failed[0].GetID() != 2 || failed[1].GetID() != 4 {
+		t.Errorf("FailedRuns = %v, want runs 2 and 4", failed)
+	}
+	if !AnyRunFailed(runs) || AnyRunFailed(runs[:1]) {
+		t.Error("AnyRunFailed disagrees with FailedRuns")
+	}
+}
+
+func TestRerun(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := NewClient(context.Background(), "token", "o", "r")
+	c.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	if err := c.RerunFailedJobs(context.Background(), 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RerunWorkflow(context.Background(), 8); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/repos/o/r/actions/runs/7/rerun-failed-jobs", "/repos/o/r/actions/runs/8/rerun"}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,122 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"golang.org/x/oauth2"
+)
+
+// AppAuth identifies a GitHub App installation that releasebot acts as.
+type AppAuth struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKey     *rsa.PrivateKey
+	// BaseURL is the API URL of a GitHub Enterprise Server instance ("" for github.com).
+	BaseURL string
+}
+
+// ParsePrivateKey parses a GitHub App private key (PEM, PKCS#1 as downloaded from GitHub, or PKCS#8).
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("private key: no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key: not an RSA key")
+	}
+	return key, nil
+}
+
+// NewAppTokenSource returns a token source of installation access tokens for the app installation. Tokens are
+// minted on first use and again shortly before each expires (they last an hour), so long releases keep working.
+func NewAppTokenSource(ctx context.Context, app AppAuth) (oauth2.TokenSource, error) {
+	if app.AppID == 0 || app.InstallationID == 0 || app.PrivateKey == nil {
+		return nil, errors.New("github app: app ID, installation ID and private key are required")
+	}
+	gh := github.NewClient(&http.Client{Transport: &appJWTTransport{app: app}})
+	if app.BaseURL != "" {
+		var err error
+		if gh, err = gh.WithEnterpriseURLs(app.BaseURL, app.BaseURL); err != nil {
+			return nil, fmt.Errorf("github enterprise URLs: %w", err)
+		}
+	}
+	return oauth2.ReuseTokenSource(nil, &appTokenSource{ctx: ctx, gh: gh, installationID: app.InstallationID}), nil
+}
+
+// appTokenRefreshMargin is how long before its expiry an installation token is replaced.
+const appTokenRefreshMargin = 5 * time.Minute
+
+// appTokenSource mints an installation access token on each call.
+type appTokenSource struct {
+	ctx            context.Context
+	gh             *github.Client
+	installationID int64
+}
+
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	tok, _, err := s.gh.Apps.CreateInstallationToken(s.ctx, s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("github app: create installation token: %w", err)
+	}
+	return &oauth2.Token{
+		AccessToken: tok.GetToken(),
+		TokenType:   "token",
+		Expiry:      tok.GetExpiresAt().Add(-appTokenRefreshMargin),
+	}, nil
+}
+
+// appJWTTransport authenticates requests as the app itself with a freshly signed JWT.
+type appJWTTransport struct {
+	app AppAuth
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := appJWT(t.app.AppID, t.app.PrivateKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// appJWT returns the RS256 JWT that authenticates as the app: issued a minute in the past to allow for clock
+// drift, and valid for nine minutes (GitHub allows at most ten).
+func appJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("github app: sign JWT: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
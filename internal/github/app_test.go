@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	for name, data := range map[string][]byte{"pkcs1": pkcs1, "pkcs8": pkcs8} {
+		got, err := ParsePrivateKey(data)
+		if err != nil || !got.Equal(key) {
+			t.Errorf("%s: key = %v, err = %v", name, got != nil, err)
+		}
+	}
+	if _, err := ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("expected error for non-PEM input")
+	}
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var minted int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/app/installations/5/access_tokens" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		jwt := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(jwt, ".")
+		if len(parts) != 3 {
+			t.Fatalf("Authorization = %q, want a JWT", r.Header.Get("Authorization"))
+		}
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, sum[:], sig); err != nil {
+			t.Errorf("JWT signature: %v", err)
+		}
+		claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var c struct {
+			Iss string `json:"iss"`
+		}
+		_ = json.Unmarshal(claims, &c)
+		if c.Iss != "42" {
+			t.Errorf("iss = %q, want 42", c.Iss)
+		}
+		minted++
+		// The first token is about to expire, so the next use mints another.
+		expires := time.Now().Add(time.Hour)
+		if minted == 1 {
+			expires = time.Now().Add(time.Minute)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "inst-%d", "expires_at": %q}`, minted, expires.UTC().Format(time.RFC3339))
+	}))
+	defer srv.Close()
+
+	ts, err := NewAppTokenSource(context.Background(), AppAuth{AppID: 42, InstallationID: 5, PrivateKey: key, BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"inst-1", "inst-2", "inst-2"} {
+		tok, err := ts.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.AccessToken != want {
+			t.Errorf("token %d = %q, want %q", i, tok.AccessToken, want)
+		}
+	}
+	if _, err := NewAppTokenSource(context.Background(), AppAuth{AppID: 42}); err == nil {
+		t.Error("expected error without installation ID and key")
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
+	"strings"
 
 	"github.com/google/go-github/v60/github"
 	"golang.org/x/oauth2"
@@ -15,17 +16,87 @@ type Client struct {
 	*github.Client
 	Owner string
 	Repo  string
+	// CommitCache, when set, is consulted before looking up the PRs for a commit and updated afterwards.
+	CommitCache CommitPRCache
+
+	httpClient *http.Client
+	hasToken   bool
+}
+
+// CommitPRCache stores the merged PRs per commit SHA (see cache.CommitPRCache).
+type CommitPRCache interface {
+	GetCommitPRs(owner, repo, sha string) ([]PullRequest, bool)
+	SetCommitPRs(owner, repo, sha string, prs []PullRequest) error
 }
 
 // NewClient builds a GitHub client. token can be empty for public repo read-only.
 func NewClient(ctx context.Context, token, owner, repo string) *Client {
-	var httpClient *http.Client
+	var ts oauth2.TokenSource
 	if token != "" {
-		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	}
+	return newClient(ctx, ts, owner, repo)
+}
+
+// newClient builds a client authorized by ts (nil for unauthenticated requests).
+func newClient(ctx context.Context, ts oauth2.TokenSource, owner, repo string) *Client {
+	httpClient := http.DefaultClient
+	if ts != nil {
 		httpClient = oauth2.NewClient(ctx, ts)
 	}
 	gh := github.NewClient(httpClient)
-	return &Client{Client: gh, Owner: owner, Repo: repo}
+	return &Client{Client: gh, Owner: owner, Repo: repo, httpClient: httpClient, hasToken: ts != nil}
+}
+
+// HasToken returns true when requests are authenticated.
+func (c *Client) HasToken() bool {
+	return c.hasToken
+}
+
+// NewEnterpriseClient builds a client for a GitHub Enterprise Server instance whose API is at baseURL
+// (e.g. https://github.example.com/api/v3/; /api/v3/ is added when missing) and whose upload API is at
+// uploadURL (default: baseURL's host). An empty baseURL means github.com, as with NewClient.
+func NewEnterpriseClient(ctx context.Context, token, baseURL, uploadURL, owner, repo string) (*Client, error) {
+	var ts oauth2.TokenSource
+	if token != "" {
+		ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	}
+	return NewClientWithTokenSource(ctx, ts, baseURL, uploadURL, owner, repo)
+}
+
+// NewClientWithTokenSource is NewEnterpriseClient with requests authorized by ts (e.g. NewAppTokenSource's
+// installation tokens, refreshed as they expire); ts can be nil for unauthenticated requests.
+func NewClientWithTokenSource(ctx context.Context, ts oauth2.TokenSource, baseURL, uploadURL, owner, repo string) (*Client, error) {
+	c := newClient(ctx, ts, owner, repo)
+	if baseURL == "" {
+		return c, nil
+	}
+	if uploadURL == "" {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("github base_url: %w", err)
+		}
+		uploadURL = u.Scheme + "://" + u.Host + "/"
+	}
+	gh, err := c.Client.WithEnterpriseURLs(baseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("github enterprise URLs: %w", err)
+	}
+	c.Client = gh
+	return c, nil
+}
+
+// WebURL returns the web URL of the GitHub host whose API is at baseURL: https://github.com when baseURL
+// is empty, else the scheme and host of baseURL without an "api." prefix (https://github.example.com).
+func WebURL(baseURL string) string {
+	if baseURL == "" {
+		return "https://github.com"
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return "https://github.com"
+	}
+	return u.Scheme + "://" + strings.TrimPrefix(u.Host, "api.")
 }
 
 // CompareResponse is a subset of compare result we need.
@@ -113,42 +184,22 @@ func (c *Client) GetPRDiff(ctx context.Context, number int) (string, error) {
 }
 
 // MergedPRsBetween returns merged PRs that appear in the commit range base..head.
-// It uses CompareCommits then for each commit fetches associated PRs and deduplicates by PR number.
+// It uses CompareCommits then looks up the PRs associated with each commit and deduplicates by PR number.
+// A *PRLookupError is returned along with the PRs found when some commits could not be looked up.
 func (c *Client) MergedPRsBetween(ctx context.Context, base, head string) ([]PullRequest, error) {
 	return c.MergedPRsBetweenWithProgress(ctx, base, head, nil, nil)
 }
 
 // MergedPRsBetweenWithProgress does MergedPRsBetween and calls report with status messages.
-// When reportProgress is non-nil, it is called with (current, total) in the PR-fetch loop instead of per-commit status lines.
+// When reportProgress is non-nil, it is called with (current, total) commits looked up instead of status lines.
 func (c *Client) MergedPRsBetweenWithProgress(ctx context.Context, base, head string, report func(string), reportProgress func(current, total int)) ([]PullRequest, error) {
 	commits, err := c.ListCommitsBetween(ctx, base, head)
 	if err != nil {
 		return nil, err
 	}
-	nCommits := len(commits)
-	if report != nil && nCommits > 0 {
-		report("Fetching PRs from GitHub...")
-	}
-	seen := make(map[int]struct{})
-	var result []PullRequest
+	shas := make([]string, len(commits))
 	for i, commit := range commits {
-		if reportProgress != nil && nCommits > 0 {
-			reportProgress(i+1, nCommits)
-		} else if report != nil && nCommits > 0 {
-			report("Fetching PRs for commit " + strconv.Itoa(i+1) + "/" + strconv.Itoa(nCommits) + "...")
-		}
-		sha := commit.GetSHA()
-		prs, err := c.PullRequestsForCommit(ctx, sha)
-		if err != nil {
-			continue
-		}
-		for _, pr := range prs {
-			if _, ok := seen[pr.Number]; ok {
-				continue
-			}
-			seen[pr.Number] = struct{}{}
-			result = append(result, pr)
-		}
+		shas[i] = commit.GetSHA()
 	}
-	return result, nil
+	return c.MergedPRsForCommits(ctx, shas, report, reportProgress)
 }
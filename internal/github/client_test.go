@@ -0,0 +1,40 @@
+package github
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewEnterpriseClient(t *testing.T) {
+	c, err := NewEnterpriseClient(context.Background(), "token", "https://ghe.example.com", "", "o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.BaseURL.String(); got != "https://ghe.example.com/api/v3/" {
+		t.Errorf("BaseURL = %q", got)
+	}
+	if got := c.UploadURL.String(); got != "https://ghe.example.com/api/uploads/" {
+		t.Errorf("UploadURL = %q", got)
+	}
+	if got := c.graphqlURL(); got != "https://ghe.example.com/api/graphql" {
+		t.Errorf("graphqlURL = %q", got)
+	}
+
+	c, err = NewEnterpriseClient(context.Background(), "", "", "", "o", "r")
+	if err != nil || c.BaseURL.String() != "https://api.github.com/" {
+		t.Errorf("empty base URL: BaseURL = %v, err = %v", c.BaseURL, err)
+	}
+}
+
+func TestWebURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                "https://github.com",
+		"https://ghe.example.com/api/v3/": "https://ghe.example.com",
+		"https://api.acme.ghe.com/":       "https://acme.ghe.com",
+	}
+	for base, want := range tests {
+		if got := WebURL(base); got != want {
+			t.Errorf("WebURL(%q) = %q, want %q", base, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v60/github"
+)
+
+// WorkflowJob re-exposes go-github's WorkflowJob for callers.
+type WorkflowJob = gh.WorkflowJob
+
+// ListWorkflowJobs returns the jobs of the run's latest attempt.
+func (c *Client) ListWorkflowJobs(ctx context.Context, runID int64) ([]*WorkflowJob, error) {
+	opts := &gh.ListWorkflowJobsOptions{Filter: "latest", ListOptions: gh.ListOptions{PerPage: 100}}
+	var all []*WorkflowJob
+	for {
+		jobs, resp, err := c.Actions.ListWorkflowJobs(ctx, c.Owner, c.Repo, runID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("list jobs for run %d: %w", runID, err)
+		}
+		all = append(all, jobs.Jobs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// logTimestampRegex matches the timestamp GitHub prefixes to every job log line.
+var logTimestampRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+
+// JobLogTail returns the last n lines of a job's log, without the per-line timestamps.
+func (c *Client) JobLogTail(ctx context.Context, jobID int64, n int) (string, error) {
+	u, _, err := c.Actions.GetWorkflowJobLogs(ctx, c.Owner, c.Repo, jobID, 1)
+	if err != nil {
+		return "", fmt.Errorf("get log url for job %d: %w", jobID, err)
+	}
+	// The log is served from a pre-signed URL; it must not receive the API token.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download log for job %d: %w", jobID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download log for job %d: status %d", jobID, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("download log for job %d: %w", jobID, err)
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = logTimestampRegex.ReplaceAllString(line, "")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// JobProgress is a job's state for progress output: the step it is on and how long it has run.
+type JobProgress struct {
+	JobID      int64
+	Workflow   string
+	Job        string
+	Status     string
+	Conclusion string
+	// Step is the running step (in progress), or the failed step (completed), when known.
+	Step string
+	// StepNumber is Step's position among the job's Steps.
+	StepNumber int
+	Steps      int
+	// Elapsed is the time since the job started (until it completed, when finished).
+	Elapsed time.Duration
+	HTMLURL string
+}
+
+// JobProgressFrom summarizes job as of now.
+func JobProgressFrom(job *WorkflowJob, now time.Time) JobProgress {
+	p := JobProgress{
+		JobID:      job.GetID(),
+		Workflow:   job.GetWorkflowName(),
+		Job:        job.GetName(),
+		Status:     job.GetStatus(),
+		Conclusion: job.GetConclusion(),
+		Steps:      len(job.Steps),
+		HTMLURL:    job.GetHTMLURL(),
+	}
+	if job.StartedAt != nil && !job.StartedAt.IsZero() {
+		end := now
+		if job.CompletedAt != nil && !job.CompletedAt.IsZero() && p.Status == "completed" {
+			end = job.CompletedAt.Time
+		}
+		p.Elapsed = end.Sub(job.StartedAt.Time)
+	}
+	for i, s := range job.Steps {
+		if s.GetStatus() == "in_progress" || (s.GetStatus() == "completed" && s.GetConclusion() == "failure") {
+			p.Step = s.GetName()
+			p.StepNumber = i + 1
+			break
+		}
+	}
+	return p
+}
+
+// IsFailed returns true when the job completed with a non-success conclusion (skipped jobs are not failures).
+func (p JobProgress) IsFailed() bool {
+	return p.Status == "completed" && p.Conclusion != "success" && p.Conclusion != "skipped" && p.Conclusion != "neutral"
+}
+
+// String returns a one-line summary, e.g. "Release / build: Run tests (step 4/9, 2m13s)" or
+// "Release / build: failure at step 4/9 Run tests (3m2s)".
+func (p JobProgress) String() string {
+	name := p.Job
+	if p.Workflow != "" {
+		name = p.Workflow + " / " + p.Job
+	}
+	var extra []string
+	state := strings.ReplaceAll(p.Status, "_", " ")
+	switch {
+	case p.Status == "completed" && p.Step != "":
+		state = fmt.Sprintf("%s at step %d/%d %s", p.Conclusion, p.StepNumber, p.Steps, p.Step)
+	case p.Status == "completed":
+		state = p.Conclusion
+	case p.Step != "":
+		state = p.Step
+		extra = append(extra, fmt.Sprintf("step %d/%d", p.StepNumber, p.Steps))
+	}
+	if p.Elapsed > 0 {
+		extra = append(extra, p.Elapsed.Round(time.Second).String())
+	}
+	if len(extra) > 0 {
+		state += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return name + ": " + state
+}
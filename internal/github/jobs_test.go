@@ -0,0 +1,83 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v60/github"
+)
+
+func TestJobProgressFrom(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(2*time.Minute + 13*time.Second)
+	steps := []*gh.TaskStep{
+		{Name: gh.String("Set up job"), Status: gh.String("completed"), Conclusion: gh.String("success")},
+		{Name: gh.String("Run tests"), Status: gh.String("in_progress")},
+		{Name: gh.String("Upload"), Status: gh.String("queued")},
+	}
+	job := &WorkflowJob{
+		ID:           gh.Int64(5),
+		Name:         gh.String("build"),
+		WorkflowName: gh.String("Release"),
+		Status:       gh.String("in_progress"),
+		StartedAt:    &gh.Timestamp{Time: start},
+		Steps:        steps,
+	}
+	p := JobProgressFrom(job, now)
+	if got, want := p.String(), "Release / build: Run tests (step 2/3, 2m13s)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if p.IsFailed() {
+		t.Error("IsFailed() = true for a running job")
+	}
+
+	steps[1].Status, steps[1].Conclusion = gh.String("completed"), gh.String("failure")
+	job.Status, job.Conclusion = gh.String("completed"), gh.String("failure")
+	job.CompletedAt = &gh.Timestamp{Time: start.Add(time.Minute)}
+	p = JobProgressFrom(job, now)
+	if got, want := p.String(), "Release / build: failure at step 2/3 Run tests (1m0s)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !p.IsFailed() {
+		t.Error("IsFailed() = false for a failed job")
+	}
+
+	p = JobProgressFrom(&WorkflowJob{Name: gh.String("lint"), Status: gh.String("queued")}, now)
+	if got, want := p.String(), "lint: queued"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestJobLogTail(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/r/actions/jobs/5/logs":
+			http.Redirect(w, r, srv.URL+"/signed/log.txt", http.StatusFound)
+		case "/signed/log.txt":
+			if r.Header.Get("Authorization") != "" {
+				t.Error("log download sent the API token")
+			}
+			_, _ = w.Write([]byte("2024-05-01T12:00:00.0000000Z one\r\n2024-05-01T12:00:01.0000000Z two\r\n2024-05-01T12:00:02.0000000Z ##[error]three\r\n"))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(context.Background(), "token", "o", "r")
+	c.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	tail, err := c.JobLogTail(context.Background(), 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "two\n##[error]three"; tail != want {
+		t.Errorf("JobLogTail = %q, want %q", tail, want)
+	}
+}
@@ -0,0 +1,396 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v60/github"
+	"github.com/johnewart/releasebot/internal/workpool"
+)
+
+const (
+	// prLookupBatchSize is the number of commits resolved per GraphQL query.
+	prLookupBatchSize = 50
+	// prLookupConcurrency is the number of GraphQL queries (or REST calls without a token) in flight.
+	prLookupConcurrency = 4
+	// maxRateLimitWaits is how many times a request waits out a rate limit before giving up.
+	maxRateLimitWaits = 3
+	// maxRateLimitPause caps a single rate-limit pause.
+	maxRateLimitPause = 15 * time.Minute
+)
+
+var commitSHARegex = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// PRLookupError reports commits whose associated PRs could not be looked up. The PRs found for the other
+// commits are still returned alongside it, so callers can warn and continue.
+type PRLookupError struct {
+	SHAs []string
+	Err  error // first underlying error
+}
+
+func (e *PRLookupError) Error() string {
+	short := make([]string, 0, len(e.SHAs))
+	for _, sha := range e.SHAs {
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		short = append(short, sha)
+	}
+	return fmt.Sprintf("could not look up PRs for %d commit(s) (%s): %v", len(e.SHAs), strings.Join(short, ", "), e.Err)
+}
+
+func (e *PRLookupError) Unwrap() error { return e.Err }
+
+// MergedPRsForCommits returns the merged PRs associated with the given commits, deduplicated by PR number in commit order
+// (e.g. only the commits touching a monorepo component's paths). With a token, commits are resolved in batches through the
+// GraphQL API; without one, through the REST API one commit at a time. Lookups run in parallel, use CommitCache when set,
+// and pause when GitHub reports a rate limit. If some commits fail, the PRs found for the rest are returned with a *PRLookupError.
+func (c *Client) MergedPRsForCommits(ctx context.Context, shas []string, report func(string), reportProgress func(current, total int)) ([]PullRequest, error) {
+	total := len(shas)
+	results := make([][]PullRequest, total)
+	var missing []int
+	for i, sha := range shas {
+		if c.CommitCache != nil {
+			if prs, ok := c.CommitCache.GetCommitPRs(c.Owner, c.Repo, sha); ok {
+				results[i] = prs
+				continue
+			}
+		}
+		missing = append(missing, i)
+	}
+	if report != nil && len(missing) > 0 {
+		report(fmt.Sprintf("Fetching PRs from GitHub for %d commit(s) (%d cached)...", len(missing), total-len(missing)))
+	}
+
+	var (
+		mu       sync.Mutex
+		done     = total - len(missing)
+		failed   []string
+		firstErr error
+	)
+	progress := func(n int) {
+		done += n
+		if reportProgress != nil {
+			reportProgress(done, total)
+		} else if report != nil {
+			report("Fetching PRs for commit " + strconv.Itoa(done) + "/" + strconv.Itoa(total) + "...")
+		}
+	}
+	if done > 0 {
+		progress(0)
+	}
+	fail := func(sha string, err error) {
+		failed = append(failed, sha)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	store := func(i int, prs []PullRequest) {
+		results[i] = prs
+		if c.CommitCache != nil {
+			_ = c.CommitCache.SetCommitPRs(c.Owner, c.Repo, shas[i], prs)
+		}
+	}
+
+	var batches [][]int
+	batchSize := 1
+	if c.hasToken {
+		batchSize = prLookupBatchSize
+	}
+	for start := 0; start < len(missing); start += batchSize {
+		end := start + batchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batches = append(batches, missing[start:end])
+	}
+	_ = workpool.ForEach(ctx, len(batches), prLookupConcurrency, func(ctx context.Context, b int) error {
+		batch := batches[b]
+		if c.hasToken {
+			batchSHAs := make([]string, len(batch))
+			for j, i := range batch {
+				batchSHAs[j] = shas[i]
+			}
+			prsBySHA, errsBySHA := c.pullRequestsForCommitsGraphQL(ctx, batchSHAs, report)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, i := range batch {
+				if err := errsBySHA[shas[i]]; err != nil {
+					fail(shas[i], err)
+				} else {
+					store(i, prsBySHA[shas[i]])
+				}
+			}
+			progress(len(batch))
+			return nil
+		}
+		i := batch[0]
+		prs, err := c.pullRequestsForCommitWithRetry(ctx, shas[i], report)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			fail(shas[i], err)
+		} else {
+			store(i, prs)
+		}
+		progress(1)
+		return nil
+	}, nil)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]struct{})
+	var result []PullRequest
+	for _, prs := range results {
+		for _, pr := range prs {
+			if _, ok := seen[pr.Number]; ok {
+				continue
+			}
+			seen[pr.Number] = struct{}{}
+			result = append(result, pr)
+		}
+	}
+	if len(failed) > 0 {
+		// Report failures in commit order.
+		order := make(map[string]int, total)
+		for i, sha := range shas {
+			order[sha] = i
+		}
+		sort.Slice(failed, func(a, b int) bool { return order[failed[a]] < order[failed[b]] })
+		return result, &PRLookupError{SHAs: failed, Err: firstErr}
+	}
+	return result, nil
+}
+
+// pullRequestsForCommitWithRetry calls PullRequestsForCommit, waiting out rate limits (REST path, no token).
+func (c *Client) pullRequestsForCommitWithRetry(ctx context.Context, sha string, report func(string)) ([]PullRequest, error) {
+	for attempt := 0; ; attempt++ {
+		prs, err := c.PullRequestsForCommit(ctx, sha)
+		if err == nil {
+			return prs, nil
+		}
+		var wait time.Duration
+		var rl *github.RateLimitError
+		var abuse *github.AbuseRateLimitError
+		switch {
+		case errors.As(err, &rl):
+			wait = time.Until(rl.Rate.Reset.Time)
+		case errors.As(err, &abuse):
+			wait = abuse.GetRetryAfter()
+		default:
+			return nil, err
+		}
+		if attempt >= maxRateLimitWaits {
+			return nil, err
+		}
+		if err := pauseForRateLimit(ctx, wait, report); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// pauseForRateLimit sleeps for wait (at least 1s, at most maxRateLimitPause), reporting the pause if report is set.
+func pauseForRateLimit(ctx context.Context, wait time.Duration, report func(string)) error {
+	if wait < time.Second {
+		wait = time.Second
+	}
+	if wait > maxRateLimitPause {
+		wait = maxRateLimitPause
+	}
+	if report != nil {
+		report(fmt.Sprintf("GitHub rate limit reached; waiting %s...", wait.Round(time.Second)))
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// graphqlURL returns the GraphQL endpoint for the client's API base URL
+// (https://api.github.com/graphql, or https://HOST/api/graphql for GitHub Enterprise Server).
+func (c *Client) graphqlURL() string {
+	base := c.BaseURL.String()
+	if strings.HasSuffix(base, "/api/v3/") {
+		return strings.TrimSuffix(base, "v3/") + "graphql"
+	}
+	return strings.TrimSuffix(base, "/") + "/graphql"
+}
+
+type graphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// graphQL posts query with variables and returns the "data" object and any GraphQL errors.
+// Rate limits (403/429 with X-RateLimit-Remaining: 0 or Retry-After) are waited out up to maxRateLimitWaits times.
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]interface{}, report func(string)) (json.RawMessage, []graphQLError, error) {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return nil, nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.graphqlURL(), bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, nil, fmt.Errorf("graphql: %w", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("graphql: read response: %w", err)
+		}
+		if wait, limited := rateLimitWait(resp); limited {
+			if attempt >= maxRateLimitWaits {
+				return nil, nil, fmt.Errorf("graphql: rate limited (HTTP %d)", resp.StatusCode)
+			}
+			if err := pauseForRateLimit(ctx, wait, report); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, nil, fmt.Errorf("graphql: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		}
+		var out struct {
+			Data   json.RawMessage `json:"data"`
+			Errors []graphQLError  `json:"errors"`
+		}
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, nil, fmt.Errorf("graphql: parse response: %w", err)
+		}
+		return out.Data, out.Errors, nil
+	}
+}
+
+// rateLimitWait returns how long to wait if resp is a rate-limit response (primary or secondary limit).
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return time.Until(time.Unix(reset, 0)), true
+		}
+		return time.Minute, true
+	}
+	return 0, resp.StatusCode == http.StatusTooManyRequests
+}
+
+// pullRequestsForCommitsGraphQL resolves the merged PRs for up to prLookupBatchSize commits in one GraphQL query.
+// Commits whose lookup failed (or the whole batch, if the request failed) are returned in errs.
+func (c *Client) pullRequestsForCommitsGraphQL(ctx context.Context, shas []string, report func(string)) (map[string][]PullRequest, map[string]error) {
+	prs := make(map[string][]PullRequest, len(shas))
+	errs := make(map[string]error)
+	var q strings.Builder
+	q.WriteString("query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) {")
+	aliases := make(map[string]string, len(shas))
+	for i, sha := range shas {
+		if !commitSHARegex.MatchString(sha) {
+			errs[sha] = fmt.Errorf("invalid commit SHA %q", sha)
+			continue
+		}
+		alias := "c" + strconv.Itoa(i)
+		aliases[alias] = sha
+		fmt.Fprintf(&q, ` %s: object(oid: "%s") { ... on Commit { associatedPullRequests(first: 10) { nodes { number title body mergedAt author { login } } } } }`, alias, sha)
+	}
+	q.WriteString(" } }")
+	if len(aliases) == 0 {
+		return prs, errs
+	}
+	data, gqlErrs, err := c.graphQL(ctx, q.String(), map[string]interface{}{"owner": c.Owner, "name": c.Repo}, report)
+	if err != nil {
+		for _, sha := range aliases {
+			errs[sha] = err
+		}
+		return prs, errs
+	}
+	for _, e := range gqlErrs {
+		// Errors with a path point at one commit alias (["repository", "c3", ...]); others fail the batch.
+		if len(e.Path) >= 2 {
+			if alias, ok := e.Path[1].(string); ok {
+				if sha, ok := aliases[alias]; ok {
+					errs[sha] = errors.New(e.Message)
+					continue
+				}
+			}
+		}
+		for _, sha := range aliases {
+			if errs[sha] == nil {
+				errs[sha] = errors.New(e.Message)
+			}
+		}
+	}
+	var parsed struct {
+		Repository map[string]*struct {
+			AssociatedPullRequests struct {
+				Nodes []struct {
+					Number   int        `json:"number"`
+					Title    string     `json:"title"`
+					Body     string     `json:"body"`
+					MergedAt *time.Time `json:"mergedAt"`
+					Author   *struct {
+						Login string `json:"login"`
+					} `json:"author"`
+				} `json:"nodes"`
+			} `json:"associatedPullRequests"`
+		} `json:"repository"`
+	}
+	if len(data) > 0 && string(data) != "null" {
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			for _, sha := range aliases {
+				errs[sha] = fmt.Errorf("graphql: parse data: %w", err)
+			}
+			return prs, errs
+		}
+	} else if len(gqlErrs) == 0 {
+		for _, sha := range aliases {
+			errs[sha] = errors.New("graphql: empty response")
+		}
+		return prs, errs
+	}
+	for alias, sha := range aliases {
+		if errs[sha] != nil {
+			continue
+		}
+		obj := parsed.Repository[alias]
+		if obj == nil {
+			continue // commit not known to GitHub (e.g. not pushed); no PRs
+		}
+		for _, n := range obj.AssociatedPullRequests.Nodes {
+			if n.MergedAt == nil {
+				continue
+			}
+			pr := PullRequest{Number: n.Number, Title: n.Title, Body: n.Body, MergedAt: n.MergedAt.Format("2006-01-02")}
+			if n.Author != nil {
+				pr.Author = n.Author.Login
+			}
+			prs[sha] = append(prs[sha], pr)
+		}
+	}
+	return prs, errs
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type memCommitCache map[string][]PullRequest
+
+func (m memCommitCache) GetCommitPRs(owner, repo, sha string) ([]PullRequest, bool) {
+	prs, ok := m[sha]
+	return prs, ok
+}
+
+func (m memCommitCache) SetCommitPRs(owner, repo, sha string, prs []PullRequest) error {
+	m[sha] = prs
+	return nil
+}
+
+func TestMergedPRsForCommits_GraphQL(t *testing.T) {
+	shaA := strings.Repeat("a", 40)
+	shaB := strings.Repeat("b", 40)
+	shaC := strings.Repeat("c", 40)
+	shaD := strings.Repeat("d", 40)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("path = %s, want /graphql", r.URL.Path)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if strings.Contains(req.Query, shaA) {
+			t.Errorf("cached commit should not be queried")
+		}
+		// c0 = shaB (merged PR #2 and an open PR), c1 = shaC (fails), c2 = shaD (same PR #2).
+		_, _ = w.Write([]byte(`{"data":{"repository":{
+			"c0":{"associatedPullRequests":{"nodes":[
+				{"number":2,"title":"Two","body":"","mergedAt":"2024-05-01T10:00:00Z","author":{"login":"bob"}},
+				{"number":3,"title":"Open","body":"","mergedAt":null,"author":null}]}},
+			"c1":null,
+			"c2":{"associatedPullRequests":{"nodes":[
+				{"number":2,"title":"Two","body":"","mergedAt":"2024-05-01T10:00:00Z","author":{"login":"bob"}}]}}}},
+			"errors":[{"message":"timeout","path":["repository","c1"]}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(context.Background(), "token", "o", "r")
+	c.BaseURL, _ = url.Parse(srv.URL + "/")
+	cached := memCommitCache{shaA: {{Number: 1, Title: "One"}}}
+	c.CommitCache = cached
+
+	prs, err := c.MergedPRsForCommits(context.Background(), []string{shaA, shaB, shaC, shaD}, nil, nil)
+	var lookupErr *PRLookupError
+	if !errors.As(err, &lookupErr) {
+		t.Fatalf("err = %v, want *PRLookupError", err)
+	}
+	if len(lookupErr.SHAs) != 1 || lookupErr.SHAs[0] != shaC {
+		t.Errorf("failed SHAs = %v, want [%s]", lookupErr.SHAs, shaC)
+	}
+	if len(prs) != 2 || prs[0].Number != 1 || prs[1].Number != 2 {
+		t.Fatalf("prs = %+v, want #1, #2", prs)
+	}
+	if prs[1].Author != "bob" || prs[1].MergedAt != "2024-05-01" {
+		t.Errorf("pr #2 = %+v", prs[1])
+	}
+	if _, ok := cached[shaB]; !ok {
+		t.Errorf("expected result for %s to be cached", shaB)
+	}
+	if _, ok := cached[shaC]; ok {
+		t.Errorf("failed commit should not be cached")
+	}
+}
+
+func TestGraphqlURL(t *testing.T) {
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"https://api.github.com/", "https://api.github.com/graphql"},
+		{"https://ghe.example.com/api/v3/", "https://ghe.example.com/api/graphql"},
+	}
+	for _, tt := range tests {
+		c := NewClient(context.Background(), "", "o", "r")
+		c.BaseURL, _ = url.Parse(tt.base)
+		if got := c.graphqlURL(); got != tt.want {
+			t.Errorf("graphqlURL(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+}
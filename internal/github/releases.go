@@ -0,0 +1,113 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	gh "github.com/google/go-github/v60/github"
+)
+
+// Release re-exposes go-github's RepositoryRelease for callers.
+type Release = gh.RepositoryRelease
+
+// ReleaseOptions describes the GitHub Release to create or update for a tag.
+type ReleaseOptions struct {
+	Tag        string
+	Name       string // default: Tag
+	Body       string // release notes; when empty, GitHub generates notes for a new release
+	Prerelease bool
+}
+
+// CreateOrUpdateRelease creates the release for opts.Tag, or updates its name, body and prerelease flag
+// when a (published) release for the tag already exists. created reports whether a new release was made.
+func (c *Client) CreateOrUpdateRelease(ctx context.Context, opts ReleaseOptions) (rel *Release, created bool, err error) {
+	name := opts.Name
+	if name == "" {
+		name = opts.Tag
+	}
+	existing, _, err := c.Repositories.GetReleaseByTag(ctx, c.Owner, c.Repo, opts.Tag)
+	if err != nil && !isNotFound(err) {
+		return nil, false, fmt.Errorf("get release %s: %w", opts.Tag, err)
+	}
+	if err == nil {
+		update := &gh.RepositoryRelease{
+			Name:       gh.String(name),
+			Prerelease: gh.Bool(opts.Prerelease),
+		}
+		if opts.Body != "" {
+			update.Body = gh.String(opts.Body)
+		}
+		rel, _, err = c.Repositories.EditRelease(ctx, c.Owner, c.Repo, existing.GetID(), update)
+		if err != nil {
+			return nil, false, fmt.Errorf("update release %s: %w", opts.Tag, err)
+		}
+		return rel, false, nil
+	}
+	create := &gh.RepositoryRelease{
+		TagName:    gh.String(opts.Tag),
+		Name:       gh.String(name),
+		Prerelease: gh.Bool(opts.Prerelease),
+	}
+	if opts.Body != "" {
+		create.Body = gh.String(opts.Body)
+	} else {
+		create.GenerateReleaseNotes = gh.Bool(true)
+	}
+	rel, _, err = c.Repositories.CreateRelease(ctx, c.Owner, c.Repo, create)
+	if err != nil {
+		return nil, false, fmt.Errorf("create release %s: %w", opts.Tag, err)
+	}
+	return rel, true, nil
+}
+
+// UploadReleaseAsset uploads the file at path to the release, named after the file's base name.
+// An existing asset with the same name is deleted first, so re-running a release replaces its assets.
+func (c *Client) UploadReleaseAsset(ctx context.Context, releaseID int64, path string) error {
+	name := filepath.Base(path)
+	opts := &gh.ListOptions{PerPage: 100}
+	for {
+		assets, resp, err := c.Repositories.ListReleaseAssets(ctx, c.Owner, c.Repo, releaseID, opts)
+		if err != nil {
+			return fmt.Errorf("list release assets: %w", err)
+		}
+		for _, a := range assets {
+			if a.GetName() != name {
+				continue
+			}
+			if _, err := c.Repositories.DeleteReleaseAsset(ctx, c.Owner, c.Repo, a.GetID()); err != nil {
+				return fmt.Errorf("delete existing asset %s: %w", name, err)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open asset: %w", err)
+	}
+	defer f.Close()
+	if _, _, err := c.Repositories.UploadReleaseAsset(ctx, c.Owner, c.Repo, releaseID, &gh.UploadOptions{Name: name}, f); err != nil {
+		return fmt.Errorf("upload asset %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteRelease deletes the release with the given ID (the tag itself is left alone).
+func (c *Client) DeleteRelease(ctx context.Context, releaseID int64) error {
+	if _, err := c.Repositories.DeleteRelease(ctx, c.Owner, c.Repo, releaseID); err != nil {
+		return fmt.Errorf("delete release %d: %w", releaseID, err)
+	}
+	return nil
+}
+
+// isNotFound returns true if err is a GitHub API 404.
+func isNotFound(err error) bool {
+	var errResp *gh.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
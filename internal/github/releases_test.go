@@ -0,0 +1,97 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateOrUpdateRelease(t *testing.T) {
+	var created, edited map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/releases/tags/v1.0.0":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/releases/tags/v1.1.0rc0":
+			_, _ = w.Write([]byte(`{"id":7,"tag_name":"v1.1.0rc0"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/releases":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = w.Write([]byte(`{"id":1,"tag_name":"v1.0.0"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/o/r/releases/7":
+			_ = json.NewDecoder(r.Body).Decode(&edited)
+			_, _ = w.Write([]byte(`{"id":7,"tag_name":"v1.1.0rc0"}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(context.Background(), "token", "o", "r")
+	c.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	rel, isNew, err := c.CreateOrUpdateRelease(context.Background(), ReleaseOptions{Tag: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isNew || rel.GetID() != 1 {
+		t.Errorf("created = %v, id = %d; want true, 1", isNew, rel.GetID())
+	}
+	if created["name"] != "v1.0.0" || created["generate_release_notes"] != true || created["body"] != nil {
+		t.Errorf("create request = %v", created)
+	}
+
+	rel, isNew, err = c.CreateOrUpdateRelease(context.Background(), ReleaseOptions{Tag: "v1.1.0rc0", Body: "## v1.1.0rc0\n", Prerelease: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isNew || rel.GetID() != 7 {
+		t.Errorf("created = %v, id = %d; want false, 7", isNew, rel.GetID())
+	}
+	if edited["body"] != "## v1.1.0rc0\n" || edited["prerelease"] != true {
+		t.Errorf("edit request = %v", edited)
+	}
+}
+
+func TestUploadReleaseAsset_ReplacesExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.tar.gz")
+	if err := os.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var deleted, uploaded bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/o/r/releases/1/assets":
+			_, _ = w.Write([]byte(`[{"id":5,"name":"app.tar.gz"},{"id":6,"name":"other.zip"}]`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/repos/o/r/releases/assets/5":
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/o/r/releases/1/assets":
+			if got := r.URL.Query().Get("name"); got != "app.tar.gz" {
+				t.Errorf("upload name = %q", got)
+			}
+			uploaded = true
+			_, _ = w.Write([]byte(`{"id":8,"name":"app.tar.gz"}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(context.Background(), "token", "o", "r")
+	c.BaseURL, _ = url.Parse(srv.URL + "/")
+	c.UploadURL, _ = url.Parse(srv.URL + "/")
+	if err := c.UploadReleaseAsset(context.Background(), 1, path); err != nil {
+		t.Fatal(err)
+	}
+	if !deleted || !uploaded {
+		t.Errorf("deleted = %v, uploaded = %v; want both", deleted, uploaded)
+	}
+}
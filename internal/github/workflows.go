@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -15,14 +16,71 @@ type WorkflowTrigger struct {
 	Name string
 	// Path is the path to the workflow file relative to repo root (e.g. .github/workflows/release.yml).
 	Path string
-	// RunsOnTagPush is true if the workflow is triggered by pushing a tag.
+	// RunsOnTagPush is true if the workflow is triggered by pushing some tag (see RunsOnTag).
 	RunsOnTagPush bool
-	// TagPatterns are glob patterns for tags (e.g. ["v*", "release-*"]). Empty means all tags.
-	// If RunsOnTagPush is true and TagPatterns is empty, the workflow runs on any tag push.
+	// TagPatterns are the on.push.tags filter patterns (e.g. ["v*", "!v*-rc*"]), evaluated with MatchFilters.
+	// If RunsOnTagPush is true and TagPatterns and TagIgnorePatterns are empty, the workflow runs on any tag push.
 	TagPatterns []string
+	// TagIgnorePatterns are the on.push.tags-ignore filter patterns; the workflow runs for tags they don't match.
+	TagIgnorePatterns []string
+	// RunsOnRelease is true if publishing a GitHub Release triggers the workflow (on.release with no types,
+	// or with the published type).
+	RunsOnRelease bool
+	// WorkflowRunOf names the workflows whose runs trigger this one (on.workflow_run.workflows).
+	WorkflowRunOf []string
+	// WorkflowRunBranches and WorkflowRunBranchesIgnore are the on.workflow_run branch filters, matched against
+	// the triggering run's branch or tag.
+	WorkflowRunBranches       []string
+	WorkflowRunBranchesIgnore []string
+
+	// Event is how a tag starts the workflow, set by WorkflowsTriggeredByTag: "push" (pushing the tag) or
+	// "release" (publishing the tag's GitHub Release), directly or through workflow_run.
+	Event string
+	// After names the workflow whose run triggers this one through workflow_run ("" when triggered directly).
+	After string
+}
+
+// Trigger events reported in WorkflowTrigger.Event.
+const (
+	EventPush    = "push"
+	EventRelease = "release"
+)
+
+// RunsOnTag returns true if pushing tag triggers the workflow directly (on.push with its tag filters).
+func (w *WorkflowTrigger) RunsOnTag(tag string) bool {
+	if !w.RunsOnTagPush {
+		return false
+	}
+	if len(w.TagIgnorePatterns) > 0 {
+		return !MatchFilters(tag, w.TagIgnorePatterns)
+	}
+	if len(w.TagPatterns) > 0 {
+		return MatchFilters(tag, w.TagPatterns)
+	}
+	return true
+}
+
+// runsAfter returns true if a run of the named workflow for ref triggers this workflow through workflow_run.
+func (w *WorkflowTrigger) runsAfter(workflow, ref string) bool {
+	found := false
+	for _, name := range w.WorkflowRunOf {
+		if name == workflow {
+			found = true
+			break
+		}
+	}
+	switch {
+	case !found:
+		return false
+	case len(w.WorkflowRunBranchesIgnore) > 0:
+		return !MatchFilters(ref, w.WorkflowRunBranchesIgnore)
+	case len(w.WorkflowRunBranches) > 0:
+		return MatchFilters(ref, w.WorkflowRunBranches)
+	}
+	return true
 }
 
-// ParseWorkflowFile parses workflow YAML and returns trigger info for tag pushes.
+// ParseWorkflowFile parses workflow YAML and returns its tag push, release and workflow_run triggers.
 // data is the file contents; path is used for WorkflowTrigger.Path (e.g. .github/workflows/foo.yml).
 func ParseWorkflowFile(data []byte, path string) (*WorkflowTrigger, error) {
 	var doc struct {
@@ -41,72 +99,95 @@ func ParseWorkflowFile(data []byte, path string) (*WorkflowTrigger, error) {
 		trigger.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
 	}
 
-	runsOnPush, tagPatterns, err := parseOnNode(&doc.On)
-	if err != nil {
+	if err := parseOnNode(&doc.On, trigger); err != nil {
 		return nil, fmt.Errorf("parse 'on' for %s: %w", path, err)
 	}
-	trigger.RunsOnTagPush = runsOnPush
-	trigger.TagPatterns = tagPatterns
 	return trigger, nil
 }
 
-// parseOnNode interprets the "on" YAML node and returns: runsOnTagPush, tagPatterns, error.
-func parseOnNode(node *yaml.Node) (runsOnTagPush bool, tagPatterns []string, err error) {
+// parseOnNode interprets the "on" YAML node (an event, a list of events, or a map of events to their
+// configuration) into trigger.
+func parseOnNode(node *yaml.Node, trigger *WorkflowTrigger) error {
 	if node == nil {
-		return false, nil, nil
+		return nil
 	}
 	switch node.Kind {
 	case yaml.ScalarNode:
-		event := strings.ToLower(strings.TrimSpace(node.Value))
-		if event == "push" {
-			return true, nil, nil // push with no filters = all tags and branches
-		}
-		return false, nil, nil
+		parseEvent(strings.ToLower(strings.TrimSpace(node.Value)), nil, trigger)
 	case yaml.SequenceNode:
+		// Events without configuration: push runs on all tags and branches, release on all activity types.
 		for _, n := range node.Content {
-			if n.Kind == yaml.ScalarNode && strings.ToLower(strings.TrimSpace(n.Value)) == "push" {
-				return true, nil, nil
+			if n.Kind == yaml.ScalarNode {
+				parseEvent(strings.ToLower(strings.TrimSpace(n.Value)), nil, trigger)
 			}
 		}
-		return false, nil, nil
 	case yaml.MappingNode:
-		for i := 0; i < len(node.Content); i += 2 {
-			if i+1 >= len(node.Content) {
-				break
-			}
+		for i := 0; i+1 < len(node.Content); i += 2 {
 			keyNode := node.Content[i]
-			valNode := node.Content[i+1]
 			if keyNode.Kind != yaml.ScalarNode {
 				continue
 			}
 			key := strings.ToLower(strings.TrimSpace(keyNode.Value))
-			if key != "push" {
+			if key == "push" {
+				// push: ... can be empty (no filters), or a map with tags/branches
+				if err := parsePushConfig(node.Content[i+1], trigger); err != nil {
+					return err
+				}
 				continue
 			}
-			// push: ... can be empty (no filters), or a map with tags/branches
-			runs, patterns, e := parsePushConfig(valNode)
-			return runs, patterns, e
+			parseEvent(key, node.Content[i+1], trigger)
 		}
-		return false, nil, nil
-	default:
-		return false, nil, nil
 	}
+	return nil
 }
 
-// parsePushConfig interprets the value of "on.push" (can be null, or map with tags/branches).
-func parsePushConfig(node *yaml.Node) (runsOnTagPush bool, tagPatterns []string, err error) {
+// parseEvent records a push, release or workflow_run event and its configuration (nil when none) in trigger.
+func parseEvent(event string, config *yaml.Node, trigger *WorkflowTrigger) {
+	switch event {
+	case "push":
+		_ = parsePushConfig(config, trigger)
+	case "release":
+		types := eventFilter(config, "types")
+		trigger.RunsOnRelease = len(types) == 0
+		for _, t := range types {
+			if strings.ToLower(t) == "published" {
+				trigger.RunsOnRelease = true
+			}
+		}
+	case "workflow_run":
+		trigger.WorkflowRunOf = eventFilter(config, "workflows")
+		trigger.WorkflowRunBranches = eventFilter(config, "branches")
+		trigger.WorkflowRunBranchesIgnore = eventFilter(config, "branches-ignore")
+	}
+}
+
+// eventFilter returns the list under key in an event's configuration mapping (nil when unset).
+func eventFilter(config *yaml.Node, key string) []string {
+	if config == nil || config.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(config.Content); i += 2 {
+		if strings.ToLower(strings.TrimSpace(config.Content[i].Value)) == key {
+			list, _ := parseStringSequence(config.Content[i+1])
+			return list
+		}
+	}
+	return nil
+}
+
+// parsePushConfig interprets the value of "on.push" (can be null, or map with tags/branches) into trigger.
+func parsePushConfig(node *yaml.Node, trigger *WorkflowTrigger) error {
 	if node == nil || node.Kind == yaml.ScalarNode {
 		// push: or push: null → runs on all push (tags and branches)
-		return true, nil, nil
+		trigger.RunsOnTagPush = true
+		return nil
 	}
 	if node.Kind != yaml.MappingNode {
-		return false, nil, nil
+		return nil
 	}
 	var hasTags, hasBranches bool
-	for i := 0; i < len(node.Content); i += 2 {
-		if i+1 >= len(node.Content) {
-			break
-		}
+	var tagPatterns, ignorePatterns []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
 		keyNode := node.Content[i]
 		valNode := node.Content[i+1]
 		if keyNode.Kind != yaml.ScalarNode {
@@ -114,33 +195,39 @@ func parsePushConfig(node *yaml.Node) (runsOnTagPush bool, tagPatterns []string,
 		}
 		key := strings.ToLower(strings.TrimSpace(keyNode.Value))
 		switch key {
-		case "tags":
+		case "tags", "tags-ignore":
 			hasTags = true
 			patterns, e := parseStringSequence(valNode)
 			if e != nil {
-				return false, nil, e
+				return e
 			}
-			tagPatterns = patterns
-		case "branches", "branches-ignore", "tags-ignore":
-			if key == "branches" {
-				hasBranches = true
+			if key == "tags" {
+				tagPatterns = patterns
+			} else {
+				ignorePatterns = patterns
 			}
-			// tags-ignore / branches-ignore don't change "runs on tag" for our purposes
+		case "branches", "branches-ignore":
+			hasBranches = true
 		case "paths", "paths-ignore":
 			// path filters apply to branch push; for tag push they're not evaluated (per GitHub docs)
 		}
 	}
-	// If only "tags" is specified, workflow runs only on tag push (matching patterns).
-	// If only "branches" is specified, workflow does not run on tag push.
-	// If both or neither, workflow runs on tag push (when tags not specified, or in addition to branches).
+	// With only branch filters the workflow does not run for tags; with tag filters it runs for the tags
+	// they accept; with neither it runs on every tag push.
 	if hasBranches && !hasTags {
-		return false, nil, nil
+		return nil
 	}
-	if hasTags && len(tagPatterns) == 0 {
+	if hasTags && len(tagPatterns) == 0 && len(ignorePatterns) == 0 {
 		// tags: [] means no tags match (GitHub: "If you define only tags and the push has only branches, the workflow won't run")
-		return false, nil, nil
+		return nil
+	}
+	if len(tagPatterns) > 0 && len(ignorePatterns) > 0 {
+		return fmt.Errorf("push: tags and tags-ignore cannot be used together")
 	}
-	return true, tagPatterns, nil
+	trigger.RunsOnTagPush = true
+	trigger.TagPatterns = tagPatterns
+	trigger.TagIgnorePatterns = ignorePatterns
+	return nil
 }
 
 func parseStringSequence(node *yaml.Node) ([]string, error) {
@@ -169,8 +256,24 @@ func parseStringSequence(node *yaml.Node) ([]string, error) {
 // WorkflowsDir is the default directory for workflow files under repo root.
 const WorkflowsDir = ".github/workflows"
 
-// ParseWorkflowsInRepo reads all workflow YAML files under repoRoot/.github/workflows and returns their trigger info.
+// ParseWorkflowsInRepo reads all workflow YAML files under repoRoot/.github/workflows and returns the
+// workflows a release tag can trigger directly: those run on tag push or on a published release.
 func ParseWorkflowsInRepo(repoRoot string) ([]*WorkflowTrigger, error) {
+	all, err := parseAllWorkflows(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	var result []*WorkflowTrigger
+	for _, w := range all {
+		if w.RunsOnTagPush || w.RunsOnRelease {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+// parseAllWorkflows parses every workflow YAML file under repoRoot/.github/workflows.
+func parseAllWorkflows(repoRoot string) ([]*WorkflowTrigger, error) {
 	dir := filepath.Join(repoRoot, WorkflowsDir)
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -199,46 +302,146 @@ func ParseWorkflowsInRepo(repoRoot string) ([]*WorkflowTrigger, error) {
 		if err != nil {
 			return nil, err
 		}
-		if trigger.RunsOnTagPush {
-			result = append(result, trigger)
-		}
+		result = append(result, trigger)
 	}
 	return result, nil
 }
 
-// TagMatchesPattern returns true if tag matches the GitHub Actions glob pattern.
-// Supports * (no slash) and **; other glob chars (?, [...]) are passed through to path.Match where sensible.
+// TagMatchesPattern returns true if tag matches a single GitHub Actions filter pattern (see MatchFilters).
 func TagMatchesPattern(tag, pattern string) bool {
-	// GitHub: * doesn't match /, ** matches multiple segments. For tag names there's no /.
-	// Normalize pattern: ** for tags is treated as *.
-	p := pattern
-	if p == "**" || p == "*" {
-		return true
-	}
-	// Replace ** with * for tag matching (tags don't have path segments).
-	p = strings.ReplaceAll(p, "**", "*")
-	matched, _ := filepath.Match(p, tag)
+	re, err := filterPatternRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(tag)
+}
+
+// MatchFilters evaluates GitHub Actions filter patterns (on.push.tags, branches, ...) against ref. Patterns
+// are applied in order and the last one matching ref decides: a pattern starting with ! excludes ref,
+// any other includes it. Patterns support * (any characters except /), ** (any characters), ? and + (zero
+// or one / one or more of the preceding character), [...] character sets and ranges, and \ escapes.
+func MatchFilters(ref string, patterns []string) bool {
+	matched := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		if TagMatchesPattern(ref, strings.TrimPrefix(p, "!")) {
+			matched = !negate
+		}
+	}
 	return matched
 }
 
-// WorkflowsTriggeredByTag returns workflow triggers that run when the given tag is pushed.
+// filterPatternRegexp translates a GitHub Actions filter pattern to an anchored regular expression.
+func filterPatternRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	// canRepeat tracks whether the last element written can take a ? or + quantifier.
+	canRepeat := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+			canRepeat = false
+		case '?', '+':
+			if canRepeat {
+				b.WriteByte(c)
+				canRepeat = false
+			} else {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				canRepeat = true
+			}
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta("["))
+				canRepeat = true
+				continue
+			}
+			b.WriteByte('[')
+			for _, r := range pattern[i+1 : i+1+end] {
+				if r == '-' {
+					b.WriteRune(r)
+				} else {
+					b.WriteString(regexp.QuoteMeta(string(r)))
+				}
+			}
+			b.WriteByte(']')
+			i += end + 1
+			canRepeat = true
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				c = pattern[i]
+			}
+			b.WriteString(regexp.QuoteMeta(string(c)))
+			canRepeat = true
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+			canRepeat = true
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// maxWorkflowRunDepth is how many workflows GitHub chains through workflow_run: an event can trigger A,
+// A's run can trigger B and B's run can trigger C, but C's run triggers nothing.
+const maxWorkflowRunDepth = 3
+
+// WorkflowsTriggeredByTag returns the workflows a release of tag will kick off: those run by pushing the tag
+// (Event "push"), those run by publishing its GitHub Release (Event "release"), and those those runs trigger
+// in turn through workflow_run (After set). A workflow is reported once, for the first way it is triggered.
 func WorkflowsTriggeredByTag(repoRoot, tag string) ([]*WorkflowTrigger, error) {
-	all, err := ParseWorkflowsInRepo(repoRoot)
+	all, err := parseAllWorkflows(repoRoot)
 	if err != nil {
 		return nil, err
 	}
 	var out []*WorkflowTrigger
-	for _, w := range all {
-		if len(w.TagPatterns) == 0 {
-			out = append(out, w)
-			continue
+	seen := make(map[string]bool)
+	add := func(w *WorkflowTrigger, event, after string) *WorkflowTrigger {
+		seen[w.Path] = true
+		t := *w
+		t.Event, t.After = event, after
+		out = append(out, &t)
+		return &t
+	}
+	for _, event := range []string{EventPush, EventRelease} {
+		var level []*WorkflowTrigger
+		for _, w := range all {
+			direct := (event == EventPush && w.RunsOnTag(tag)) || (event == EventRelease && w.RunsOnRelease)
+			if direct && !seen[w.Path] {
+				level = append(level, add(w, event, ""))
+			}
 		}
-		for _, pat := range w.TagPatterns {
-			if TagMatchesPattern(tag, pat) {
-				out = append(out, w)
-				break
+		for depth := 1; depth < maxWorkflowRunDepth && len(level) > 0; depth++ {
+			var next []*WorkflowTrigger
+			for _, parent := range level {
+				for _, w := range all {
+					if !seen[w.Path] && w.runsAfter(parent.Name, tag) {
+						next = append(next, add(w, event, parent.Name))
+					}
+				}
 			}
+			level = next
 		}
 	}
 	return out, nil
 }
+
+// TagPushWorkflows returns the workflows pushing the tag runs directly: the runs to wait for after a tag push,
+// since release-triggered workflows start later and workflow_run runs are not tied to the tag's commit.
+func TagPushWorkflows(triggers []*WorkflowTrigger) []*WorkflowTrigger {
+	var out []*WorkflowTrigger
+	for _, t := range triggers {
+		if t.Event == EventPush && t.After == "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
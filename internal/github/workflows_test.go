@@ -1,6 +1,8 @@
 package github
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -92,6 +94,15 @@ func TestTagMatchesPattern(t *testing.T) {
 		{"foo", "v*", false},
 		{"v1.0.0", "*", true},
 		{"any", "**", true},
+		{"release/v1", "*", false},
+		{"release/v1", "release/**", true},
+		{"v1.0.0", "v[0-9].[0-9].[0-9]", true},
+		{"v10.0.0", "v[0-9].[0-9].[0-9]", false},
+		{"v10.0.0", "v[0-9]+.*", true},
+		{"v1.0.0", "vv?1.*", true},
+		{"v1.0.0", "v1.0.?", false},
+		{"v1.0.0+build", "v*\\+build", true},
+		{"v1.0.0", "v*-rc*", false},
 	}
 	for _, tt := range tests {
 		got := TagMatchesPattern(tt.tag, tt.pattern)
@@ -100,3 +111,106 @@ func TestTagMatchesPattern(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchFilters(t *testing.T) {
+	patterns := []string{"v*", "!v*-rc*", "v2.0.0-rc1"}
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"v1.0.0", true},
+		{"v1.0.0-rc1", false},
+		{"v2.0.0-rc1", true},
+		{"release-1", false},
+	}
+	for _, tt := range tests {
+		if got := MatchFilters(tt.tag, patterns); got != tt.want {
+			t.Errorf("MatchFilters(%q, %v) = %v, want %v", tt.tag, patterns, got, tt.want)
+		}
+	}
+}
+
+func TestParseWorkflowFile_TagsIgnore(t *testing.T) {
+	yaml := `on:
+  push:
+    branches: [main]
+    tags-ignore: ['*-rc*']
+jobs: {}
+`
+	trigger, err := ParseWorkflowFile([]byte(yaml), ".github/workflows/ci.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !trigger.RunsOnTag("v1.0.0") || trigger.RunsOnTag("v1.0.0-rc1") {
+		t.Errorf("RunsOnTag: got %v for v1.0.0, %v for v1.0.0-rc1; want true, false", trigger.RunsOnTag("v1.0.0"), trigger.RunsOnTag("v1.0.0-rc1"))
+	}
+
+	yaml = `on:
+  push:
+    branches-ignore: [wip]
+jobs: {}
+`
+	trigger, err = ParseWorkflowFile([]byte(yaml), ".github/workflows/ci.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger.RunsOnTagPush {
+		t.Error("expected RunsOnTagPush false when only branches-ignore specified")
+	}
+}
+
+func TestWorkflowsTriggeredByTag(t *testing.T) {
+	repo := t.TempDir()
+	dir := filepath.Join(repo, WorkflowsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	workflows := map[string]string{
+		"release.yml":  "name: Release\non:\n  push:\n    tags: ['v*', '!v*-rc*']\n",
+		"ci.yml":       "name: CI\non:\n  push:\n    branches: [main]\n",
+		"publish.yml":  "name: Publish\non:\n  workflow_run:\n    workflows: [Release]\n    types: [completed]\n",
+		"notify.yml":   "name: Notify\non:\n  workflow_run:\n    workflows: [Publish]\n",
+		"too-deep.yml": "name: TooDeep\non:\n  workflow_run:\n    workflows: [Notify]\n",
+		"docs.yml":     "name: Docs\non:\n  release:\n    types: [published]\n",
+		"drafts.yml":   "name: Drafts\non:\n  release:\n    types: [created]\n",
+	}
+	for name, data := range workflows {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	triggers, err := WorkflowsTriggeredByTag(repo, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, w := range triggers {
+		got[w.Name] = w.Event + "/" + w.After
+	}
+	want := map[string]string{
+		"Release": "push/",
+		"Publish": "push/Release",
+		"Notify":  "push/Publish",
+		"Docs":    "release/",
+	}
+	if len(got) != len(want) {
+		t.Errorf("WorkflowsTriggeredByTag = %v, want %v", got, want)
+	}
+	for name, v := range want {
+		if got[name] != v {
+			t.Errorf("%s: got %q, want %q", name, got[name], v)
+		}
+	}
+	if push := TagPushWorkflows(triggers); len(push) != 1 || push[0].Name != "Release" {
+		t.Errorf("TagPushWorkflows = %v, want [Release]", push)
+	}
+
+	triggers, err = WorkflowsTriggeredByTag(repo, "v1.0.0-rc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 1 || triggers[0].Name != "Docs" {
+		t.Errorf("rc tag: got %d workflows, want only Docs", len(triggers))
+	}
+}
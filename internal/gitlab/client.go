@@ -0,0 +1,180 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultURL is GitLab.com.
+const DefaultURL = "https://gitlab.com"
+
+// Client wraps the GitLab REST API (v4) for one project: merge requests, pipelines and releases.
+type Client struct {
+	// URL is the GitLab instance URL (default DefaultURL); the API is served under /api/v4.
+	URL string
+	// Project is the project path with its namespace (e.g. group/subgroup/project).
+	Project string
+	// Token is sent as PRIVATE-TOKEN when set (a personal, project or group access token).
+	Token string
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for project on the instance at instanceURL (DefaultURL when empty).
+// token can be empty for read-only access to public projects.
+func NewClient(instanceURL, token, project string) *Client {
+	if instanceURL == "" {
+		instanceURL = DefaultURL
+	}
+	return &Client{URL: strings.TrimSuffix(instanceURL, "/"), Project: project, Token: token, HTTPClient: http.DefaultClient}
+}
+
+// HasToken returns true when requests are authenticated.
+func (c *Client) HasToken() bool {
+	return c.Token != ""
+}
+
+// WebURL returns the project's web URL (e.g. https://gitlab.com/group/project).
+func (c *Client) WebURL() string {
+	return c.URL + "/" + c.Project
+}
+
+// MergeRequestURL returns the web URL of merge request iid.
+func (c *Client) MergeRequestURL(iid int) string {
+	return fmt.Sprintf("%s/-/merge_requests/%d", c.WebURL(), iid)
+}
+
+// APIError is a non-2xx response from the GitLab API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("gitlab %s %s: status %d", e.Method, e.Path, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// IsNotFound returns true if err is a 404 from the GitLab API.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// projectPath returns the API path for the project followed by the given segments.
+func (c *Client) projectPath(segments ...string) string {
+	p := "projects/" + url.PathEscape(c.Project)
+	for _, s := range segments {
+		p += "/" + s
+	}
+	return p
+}
+
+// request sends an API request; body is JSON-encoded unless it is an io.Reader (sent with contentType).
+// The response is decoded into out when non-nil. Returns the response (body closed) for pagination headers.
+func (c *Client) request(ctx context.Context, method, path string, query url.Values, body interface{}, contentType string, out interface{}) (*http.Response, error) {
+	u := c.URL + "/api/v4/" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		r = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode}
+		var msg struct {
+			Message interface{} `json:"message"`
+			Error   string      `json:"error"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&msg) == nil {
+			if msg.Message != nil {
+				apiErr.Message = fmt.Sprint(msg.Message)
+			} else {
+				apiErr.Message = msg.Error
+			}
+		}
+		return resp, apiErr
+	}
+	if out != nil {
+		if w, ok := out.(io.Writer); ok {
+			_, err = io.Copy(w, resp.Body)
+			return resp, err
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp, fmt.Errorf("gitlab %s %s: parse response: %w", method, path, err)
+		}
+	}
+	return resp, nil
+}
+
+// get fetches one resource into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	_, err := c.request(ctx, http.MethodGet, path, query, nil, "", out)
+	return err
+}
+
+// getAll fetches every page of a list endpoint, calling add with each page's JSON array.
+func (c *Client) getAll(ctx context.Context, path string, query url.Values, add func(page json.RawMessage) error) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", "100")
+	for page := 1; ; {
+		query.Set("page", strconv.Itoa(page))
+		var raw json.RawMessage
+		resp, err := c.request(ctx, http.MethodGet, path, query, nil, "", &raw)
+		if err != nil {
+			return err
+		}
+		if err := add(raw); err != nil {
+			return err
+		}
+		next, _ := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+		if next <= page {
+			return nil
+		}
+		page = next
+	}
+}
@@ -0,0 +1,166 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnewart/releasebot/internal/github"
+	"github.com/johnewart/releasebot/internal/workpool"
+)
+
+// mrLookupConcurrency is the number of commit lookups in flight.
+const mrLookupConcurrency = 4
+
+// mergeRequest is the subset of a GitLab merge request that we read.
+type mergeRequest struct {
+	IID         int        `json:"iid"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	MergedAt    *time.Time `json:"merged_at"`
+	Author      struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// commit is the subset of a GitLab commit that we read.
+type commit struct {
+	ID string `json:"id"`
+}
+
+// ListCommitsBetween returns the commits in base..head, oldest first (the repository compare API).
+func (c *Client) ListCommitsBetween(ctx context.Context, base, head string) ([]string, error) {
+	var cmp struct {
+		Commits []commit `json:"commits"`
+	}
+	q := url.Values{"from": {base}, "to": {head}, "straight": {"false"}}
+	if err := c.get(ctx, c.projectPath("repository", "compare"), q, &cmp); err != nil {
+		return nil, fmt.Errorf("compare %s...%s: %w", base, head, err)
+	}
+	shas := make([]string, len(cmp.Commits))
+	for i, cm := range cmp.Commits {
+		shas[i] = cm.ID
+	}
+	return shas, nil
+}
+
+// MergedMRsForCommit returns the merged merge requests that contain the commit.
+func (c *Client) MergedMRsForCommit(ctx context.Context, sha string) ([]github.PullRequest, error) {
+	var result []github.PullRequest
+	err := c.getAll(ctx, c.projectPath("repository", "commits", sha, "merge_requests"), nil, func(page json.RawMessage) error {
+		var mrs []mergeRequest
+		if err := json.Unmarshal(page, &mrs); err != nil {
+			return fmt.Errorf("parse merge requests: %w", err)
+		}
+		for _, mr := range mrs {
+			if mr.State != "merged" {
+				continue
+			}
+			pr := github.PullRequest{Number: mr.IID, Title: mr.Title, Body: mr.Description, Author: mr.Author.Username}
+			if mr.MergedAt != nil {
+				pr.MergedAt = mr.MergedAt.Format("2006-01-02")
+			}
+			result = append(result, pr)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list merge requests for commit %s: %w", sha, err)
+	}
+	return result, nil
+}
+
+// MergedPRsBetweenWithProgress returns the merged merge requests of the commits in base..head (see
+// MergedPRsForCommits). The name matches github.Client so both serve as the changelog's change source.
+func (c *Client) MergedPRsBetweenWithProgress(ctx context.Context, base, head string, report func(string), reportProgress func(current, total int)) ([]github.PullRequest, error) {
+	shas, err := c.ListCommitsBetween(ctx, base, head)
+	if err != nil {
+		return nil, err
+	}
+	return c.MergedPRsForCommits(ctx, shas, report, reportProgress)
+}
+
+// MergedPRsForCommits returns the merged merge requests containing the given commits, deduplicated by IID in
+// commit order. Lookups run in parallel; if some fail, the merge requests found for the rest are returned
+// with a *github.PRLookupError.
+func (c *Client) MergedPRsForCommits(ctx context.Context, shas []string, report func(string), reportProgress func(current, total int)) ([]github.PullRequest, error) {
+	if report != nil && len(shas) > 0 {
+		report(fmt.Sprintf("Fetching merge requests from GitLab for %d commit(s)...", len(shas)))
+	}
+	results := make([][]github.PullRequest, len(shas))
+	var (
+		mu       sync.Mutex
+		failed   []string
+		firstErr error
+	)
+	progress := reportProgress
+	if progress == nil && report != nil {
+		progress = func(current, total int) {
+			report("Fetching merge requests for commit " + strconv.Itoa(current) + "/" + strconv.Itoa(total) + "...")
+		}
+	}
+	_ = workpool.ForEach(ctx, len(shas), mrLookupConcurrency, func(ctx context.Context, i int) error {
+		prs, err := c.MergedMRsForCommit(ctx, shas[i])
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			failed = append(failed, shas[i])
+			if firstErr == nil {
+				firstErr = err
+			}
+			return nil
+		}
+		results[i] = prs
+		return nil
+	}, progress)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	seen := make(map[int]struct{})
+	var result []github.PullRequest
+	for _, prs := range results {
+		for _, pr := range prs {
+			if _, ok := seen[pr.Number]; ok {
+				continue
+			}
+			seen[pr.Number] = struct{}{}
+			result = append(result, pr)
+		}
+	}
+	if len(failed) > 0 {
+		return result, &github.PRLookupError{SHAs: failed, Err: firstErr}
+	}
+	return result, nil
+}
+
+// GetPRDiff returns the diff of merge request iid, one "diff --git" block per changed file.
+func (c *Client) GetPRDiff(ctx context.Context, iid int) (string, error) {
+	var b strings.Builder
+	err := c.getAll(ctx, c.projectPath("merge_requests", strconv.Itoa(iid), "diffs"), nil, func(page json.RawMessage) error {
+		var diffs []struct {
+			OldPath string `json:"old_path"`
+			NewPath string `json:"new_path"`
+			Diff    string `json:"diff"`
+		}
+		if err := json.Unmarshal(page, &diffs); err != nil {
+			return fmt.Errorf("parse diffs: %w", err)
+		}
+		for _, d := range diffs {
+			fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n%s", d.OldPath, d.NewPath, d.OldPath, d.NewPath, d.Diff)
+			if !strings.HasSuffix(d.Diff, "\n") {
+				b.WriteString("\n")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("get merge request diff: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,57 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnewart/releasebot/internal/github"
+)
+
+func TestMergedPRsForCommits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "token" {
+			t.Errorf("PRIVATE-TOKEN = %q", got)
+		}
+		prefix := "/api/v4/projects/group%2Fproj/repository/commits/"
+		path := r.URL.EscapedPath()
+		if !strings.HasPrefix(path, prefix) || !strings.HasSuffix(path, "/merge_requests") {
+			t.Errorf("unexpected path %s", path)
+			http.NotFound(w, r)
+			return
+		}
+		switch sha := strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/merge_requests"); sha {
+		case "a":
+			// Second page holds the merged MR; the first holds one still open.
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[{"iid": 9, "title": "Draft", "state": "opened"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"iid": 1, "title": "Add x", "state": "merged", "merged_at": "2024-05-01T10:00:00Z", "author": {"username": "ann"}}]`)
+		case "b":
+			fmt.Fprint(w, `[{"iid": 1, "title": "Add x", "state": "merged"}, {"iid": 2, "title": "Fix y", "state": "merged"}]`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message": "boom"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "group/proj")
+	prs, err := c.MergedPRsForCommits(context.Background(), []string{"a", "b", "c"}, nil, nil)
+	var lookupErr *github.PRLookupError
+	if !errors.As(err, &lookupErr) || len(lookupErr.SHAs) != 1 || lookupErr.SHAs[0] != "c" {
+		t.Fatalf("err = %v, want PRLookupError for c", err)
+	}
+	if len(prs) != 2 || prs[0].Number != 1 || prs[1].Number != 2 {
+		t.Fatalf("prs = %+v, want !1 and !2", prs)
+	}
+	if prs[0].Author != "ann" || prs[0].MergedAt != "2024-05-01" {
+		t.Errorf("prs[0] = %+v", prs[0])
+	}
+}
@@ -0,0 +1,126 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pipeline is the subset of a GitLab pipeline that we read.
+type Pipeline struct {
+	ID     int64  `json:"id"`
+	IID    int64  `json:"iid"`
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Ref    string `json:"ref"`
+	SHA    string `json:"sha"`
+	WebURL string `json:"web_url"`
+}
+
+// IsFinished returns true when the pipeline is no longer running or waiting to run. A pipeline waiting on a
+// manual job ("manual") is finished: nothing more runs without someone starting it.
+func (p *Pipeline) IsFinished() bool {
+	switch p.Status {
+	case "success", "failed", "canceled", "skipped", "manual":
+		return true
+	}
+	return false
+}
+
+// IsFailed returns true when the pipeline failed or was canceled.
+func (p *Pipeline) IsFailed() bool {
+	return p.Status == "failed" || p.Status == "canceled"
+}
+
+// ListPipelinesForTag returns the pipelines run for tag (ref) at sha, newest first.
+func (c *Client) ListPipelinesForTag(ctx context.Context, tag, sha string) ([]*Pipeline, error) {
+	var all []*Pipeline
+	q := url.Values{"ref": {tag}, "sha": {sha}}
+	err := c.getAll(ctx, c.projectPath("pipelines"), q, func(page json.RawMessage) error {
+		var pipelines []*Pipeline
+		if err := json.Unmarshal(page, &pipelines); err != nil {
+			return fmt.Errorf("parse pipelines: %w", err)
+		}
+		all = append(all, pipelines...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list pipelines for %s: %w", tag, err)
+	}
+	return all, nil
+}
+
+// RetryPipeline re-runs the failed and canceled jobs of a pipeline.
+func (c *Client) RetryPipeline(ctx context.Context, id int64) error {
+	if _, err := c.request(ctx, http.MethodPost, c.projectPath("pipelines", strconv.FormatInt(id, 10), "retry"), nil, nil, "", nil); err != nil {
+		return fmt.Errorf("retry pipeline %d: %w", id, err)
+	}
+	return nil
+}
+
+// Job is the subset of a GitLab CI job that we read.
+type Job struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Stage      string     `json:"stage"`
+	Status     string     `json:"status"`
+	WebURL     string     `json:"web_url"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	// AllowFailure jobs do not fail the pipeline.
+	AllowFailure bool `json:"allow_failure"`
+}
+
+// IsFailed returns true when the job failed in a way that fails its pipeline.
+func (j *Job) IsFailed() bool {
+	return j.Status == "failed" && !j.AllowFailure
+}
+
+// String returns a one-line summary, e.g. "test / unit: running (2m13s)".
+func (j *Job) String() string {
+	s := j.Stage + " / " + j.Name + ": " + j.Status
+	if j.StartedAt != nil {
+		end := time.Now()
+		if j.FinishedAt != nil {
+			end = *j.FinishedAt
+		}
+		s += " (" + end.Sub(*j.StartedAt).Round(time.Second).String() + ")"
+	}
+	return s
+}
+
+// ListPipelineJobs returns the jobs of a pipeline (the latest attempt of retried jobs).
+func (c *Client) ListPipelineJobs(ctx context.Context, pipelineID int64) ([]*Job, error) {
+	var all []*Job
+	err := c.getAll(ctx, c.projectPath("pipelines", strconv.FormatInt(pipelineID, 10), "jobs"), nil, func(page json.RawMessage) error {
+		var jobs []*Job
+		if err := json.Unmarshal(page, &jobs); err != nil {
+			return fmt.Errorf("parse jobs: %w", err)
+		}
+		all = append(all, jobs...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list jobs for pipeline %d: %w", pipelineID, err)
+	}
+	return all, nil
+}
+
+// JobLogTail returns the last n lines of a job's log.
+func (c *Client) JobLogTail(ctx context.Context, jobID int64, n int) (string, error) {
+	var buf bytes.Buffer
+	if _, err := c.request(ctx, http.MethodGet, c.projectPath("jobs", strconv.FormatInt(jobID, 10), "trace"), nil, nil, "", &buf); err != nil {
+		return "", fmt.Errorf("get log for job %d: %w", jobID, err)
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(buf.String(), "\r\n", "\n"), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,126 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Release is the subset of a GitLab Release that we read.
+type Release struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Links       struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// ReleaseOptions describes the GitLab Release to create or update for a tag.
+type ReleaseOptions struct {
+	Tag  string
+	Name string // default: Tag
+	Body string // release notes (the description)
+}
+
+// CreateOrUpdateRelease creates the release for opts.Tag, or updates its name and description when a release
+// for the tag already exists. created reports whether a new release was made.
+func (c *Client) CreateOrUpdateRelease(ctx context.Context, opts ReleaseOptions) (rel *Release, created bool, err error) {
+	name := opts.Name
+	if name == "" {
+		name = opts.Tag
+	}
+	path := c.projectPath("releases", url.PathEscape(opts.Tag))
+	var existing Release
+	err = c.get(ctx, path, nil, &existing)
+	if err != nil && !IsNotFound(err) {
+		return nil, false, fmt.Errorf("get release %s: %w", opts.Tag, err)
+	}
+	body := map[string]string{"name": name}
+	if opts.Body != "" {
+		body["description"] = opts.Body
+	}
+	rel = &Release{}
+	if err == nil {
+		if _, err := c.request(ctx, http.MethodPut, path, nil, body, "", rel); err != nil {
+			return nil, false, fmt.Errorf("update release %s: %w", opts.Tag, err)
+		}
+		return rel, false, nil
+	}
+	body["tag_name"] = opts.Tag
+	if _, err := c.request(ctx, http.MethodPost, c.projectPath("releases"), nil, body, "", rel); err != nil {
+		return nil, false, fmt.Errorf("create release %s: %w", opts.Tag, err)
+	}
+	return rel, true, nil
+}
+
+// DeleteRelease deletes the release for tag (the tag itself is kept).
+func (c *Client) DeleteRelease(ctx context.Context, tag string) error {
+	if _, err := c.request(ctx, http.MethodDelete, c.projectPath("releases", url.PathEscape(tag)), nil, nil, "", nil); err != nil {
+		return fmt.Errorf("delete release %s: %w", tag, err)
+	}
+	return nil
+}
+
+// releaseLink is a GitLab Release asset link.
+type releaseLink struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// UploadReleaseAsset uploads the file at path to the project and links it from the release for tag, named
+// after the file's base name. An existing link with the same name is deleted first, so re-running a release
+// replaces its assets.
+func (c *Client) UploadReleaseAsset(ctx context.Context, tag, path string) error {
+	name := filepath.Base(path)
+	linksPath := c.projectPath("releases", url.PathEscape(tag), "assets", "links")
+	var links []releaseLink
+	if err := c.get(ctx, linksPath, nil, &links); err != nil {
+		return fmt.Errorf("list release links: %w", err)
+	}
+	for _, l := range links {
+		if l.Name != name {
+			continue
+		}
+		if _, err := c.request(ctx, http.MethodDelete, linksPath+"/"+strconv.FormatInt(l.ID, 10), nil, nil, "", nil); err != nil {
+			return fmt.Errorf("delete release link %s: %w", name, err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	var upload struct {
+		// URL is relative to the project's web URL (/uploads/<secret>/<name>).
+		URL string `json:"url"`
+	}
+	if _, err := c.request(ctx, http.MethodPost, c.projectPath("uploads"), nil, &buf, w.FormDataContentType(), &upload); err != nil {
+		return fmt.Errorf("upload %s: %w", name, err)
+	}
+	link := map[string]string{"name": name, "url": c.WebURL() + upload.URL, "link_type": "package"}
+	if _, err := c.request(ctx, http.MethodPost, linksPath, nil, link, "", nil); err != nil {
+		return fmt.Errorf("link %s to release %s: %w", name, tag, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateOrUpdateRelease(t *testing.T) {
+	existing := map[string]bool{"v1.0.0": true}
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.EscapedPath())
+		var body map[string]string
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			tag := r.URL.Path[len("/api/v4/projects/g/p/releases/"):]
+			if !existing[tag] {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"message": "404 Not Found"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"tag_name": %q}`, tag)
+		case http.MethodPut, http.MethodPost:
+			if body["description"] != "notes" {
+				t.Errorf("%s description = %q", r.Method, body["description"])
+			}
+			fmt.Fprintf(w, `{"tag_name": %q, "name": %q}`, body["tag_name"], body["name"])
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "token", "g/p")
+	ctx := context.Background()
+	if _, created, err := c.CreateOrUpdateRelease(ctx, ReleaseOptions{Tag: "v1.0.0", Body: "notes"}); err != nil || created {
+		t.Fatalf("update: created=%v err=%v", created, err)
+	}
+	rel, created, err := c.CreateOrUpdateRelease(ctx, ReleaseOptions{Tag: "v1.1.0", Body: "notes"})
+	if err != nil || !created {
+		t.Fatalf("create: created=%v err=%v", created, err)
+	}
+	if rel.TagName != "v1.1.0" || rel.Name != "v1.1.0" {
+		t.Errorf("created release = %+v", rel)
+	}
+	want := []string{
+		"GET /api/v4/projects/g%2Fp/releases/v1.0.0",
+		"PUT /api/v4/projects/g%2Fp/releases/v1.0.0",
+		"GET /api/v4/projects/g%2Fp/releases/v1.1.0",
+		"POST /api/v4/projects/g%2Fp/releases",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package goproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// DefaultProxyURL is the public Go module proxy.
+const DefaultProxyURL = "https://proxy.golang.org/"
+
+// Client checks module versions on a Go module proxy (GOPROXY protocol).
+type Client struct {
+	// ProxyURL is the proxy base URL (default DefaultProxyURL).
+	ProxyURL string
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+var _ artifact.Checker = (*Client)(nil)
+
+// NewClient returns a client for proxyURL (DefaultProxyURL when empty).
+func NewClient(proxyURL string) *Client {
+	if proxyURL == "" {
+		proxyURL = DefaultProxyURL
+	}
+	return &Client{ProxyURL: proxyURL, HTTPClient: http.DefaultClient}
+}
+
+// Check returns true if the module (e.g. github.com/org/repo) is on proxy.golang.org. If version is non-empty
+// (with or without a leading "v"), returns true only when the proxy serves that version. Returns false and nil
+// on 404 or 410 (the proxy's answer for unknown modules and versions).
+func Check(ctx context.Context, module, version string) (bool, error) {
+	return NewClient("").Check(ctx, module, version)
+}
+
+// Registry returns the proxy host.
+func (c *Client) Registry() string {
+	return artifact.Host(c.ProxyURL)
+}
+
+// Check returns true if the module version is served (see the package-level Check), using GET
+// /<module>/@v/<version>.info, or /<module>/@v/list (non-empty) when version is empty. Requesting the
+// .info also makes proxy.golang.org fetch a newly pushed tag instead of waiting for its next refresh.
+func (c *Client) Check(ctx context.Context, module, version string) (bool, error) {
+	module = strings.TrimSpace(module)
+	if module == "" {
+		return false, fmt.Errorf("module path is required")
+	}
+	u := strings.TrimSuffix(c.ProxyURL, "/") + "/" + EscapePath(module) + "/@v/"
+	if version = strings.TrimSpace(version); version != "" {
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+		u += EscapePath(version) + ".info"
+	} else {
+		u += "list"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return false, nil
+	default:
+		return false, &artifact.StatusError{Host: c.Registry(), StatusCode: resp.StatusCode, Resource: u}
+	}
+	if version != "" {
+		return true, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(body)) != "", nil
+}
+
+// EscapePath applies the module proxy case encoding: each upper-case letter becomes "!" and its lower-case
+// form (github.com/BurntSushi/toml → github.com/!burnt!sushi/toml).
+func EscapePath(p string) string {
+	var b strings.Builder
+	for _, r := range p {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
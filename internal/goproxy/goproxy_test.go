@@ -0,0 +1,39 @@
+package goproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEscapePath(t *testing.T) {
+	if got := EscapePath("github.com/BurntSushi/toml"); got != "github.com/!burnt!sushi/toml" {
+		t.Errorf("EscapePath = %q", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!acme/tool/@v/v1.2.3.info":
+			_, _ = w.Write([]byte(`{"Version":"v1.2.3"}`))
+		case "/github.com/!acme/tool/@v/list":
+			_, _ = w.Write([]byte("v1.2.3\n"))
+		default:
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	for _, tt := range []struct {
+		version string
+		want    bool
+	}{{"1.2.3", true}, {"v1.2.3", true}, {"", true}, {"v1.2.4", false}} {
+		got, err := c.Check(context.Background(), "github.com/Acme/tool", tt.version)
+		if err != nil || got != tt.want {
+			t.Errorf("Check(%q) = %v, %v; want %v", tt.version, got, err, tt.want)
+		}
+	}
+}
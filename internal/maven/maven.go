@@ -0,0 +1,93 @@
+package maven
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// DefaultRepositoryURL is Maven Central.
+const DefaultRepositoryURL = "https://repo1.maven.org/maven2/"
+
+// Client checks artifacts in a Maven repository layout.
+type Client struct {
+	// RepositoryURL is the repository base URL (default DefaultRepositoryURL).
+	RepositoryURL string
+	// Username and Password are sent with basic auth when Username is set (private repositories).
+	Username string
+	Password string
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+var _ artifact.Checker = (*Client)(nil)
+
+// NewClient returns a client for repositoryURL (DefaultRepositoryURL when empty).
+func NewClient(repositoryURL string) *Client {
+	if repositoryURL == "" {
+		repositoryURL = DefaultRepositoryURL
+	}
+	return &Client{RepositoryURL: repositoryURL, HTTPClient: http.DefaultClient}
+}
+
+// Check returns true if the artifact (groupId:artifactId, e.g. com.example:my-lib) exists on Maven Central.
+// If version is non-empty, returns true only when that version's POM is published. Returns false and nil on 404.
+func Check(ctx context.Context, coordinate, version string) (bool, error) {
+	return NewClient("").Check(ctx, coordinate, version)
+}
+
+// Registry returns the repository host.
+func (c *Client) Registry() string {
+	return artifact.Host(c.RepositoryURL)
+}
+
+// Check returns true if the artifact exists (see the package-level Check): the version's POM when version is
+// set, else the artifact's maven-metadata.xml.
+func (c *Client) Check(ctx context.Context, coordinate, version string) (bool, error) {
+	group, artifactID, err := ParseCoordinate(coordinate)
+	if err != nil {
+		return false, err
+	}
+	u := strings.TrimSuffix(c.RepositoryURL, "/") + "/" + strings.ReplaceAll(group, ".", "/") + "/" + artifactID + "/"
+	if version = strings.TrimSpace(version); version != "" {
+		u += version + "/" + artifactID + "-" + version + ".pom"
+	} else {
+		u += "maven-metadata.xml"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return false, err
+	}
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, &artifact.StatusError{Host: c.Registry(), StatusCode: resp.StatusCode, Resource: u}
+	}
+}
+
+// ParseCoordinate splits groupId:artifactId. Extra fields (packaging, classifier, version) are rejected.
+func ParseCoordinate(coordinate string) (group, artifactID string, err error) {
+	parts := strings.Split(strings.TrimSpace(coordinate), ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("maven artifact %q: expected groupId:artifactId", coordinate)
+	}
+	return parts[0], parts[1], nil
+}
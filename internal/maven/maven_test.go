@@ -0,0 +1,34 @@
+package maven
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/maven2/com/example/my-lib/1.2.3/my-lib-1.2.3.pom", "/maven2/com/example/my-lib/maven-metadata.xml":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL + "/maven2/")
+	for _, tt := range []struct {
+		version string
+		want    bool
+	}{{"1.2.3", true}, {"", true}, {"1.2.4", false}} {
+		got, err := c.Check(context.Background(), "com.example:my-lib", tt.version)
+		if err != nil || got != tt.want {
+			t.Errorf("Check(%q) = %v, %v; want %v", tt.version, got, err, tt.want)
+		}
+	}
+	if _, err := c.Check(context.Background(), "my-lib", ""); err == nil {
+		t.Error("Check without groupId: expected error")
+	}
+}
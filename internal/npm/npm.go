@@ -0,0 +1,92 @@
+package npm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// DefaultRegistryURL is the public npm registry.
+const DefaultRegistryURL = "https://registry.npmjs.org/"
+
+// Client checks packages on an npm registry.
+type Client struct {
+	// RegistryURL is the registry base URL (default DefaultRegistryURL).
+	RegistryURL string
+	// Token is sent as a bearer token when set (private registries).
+	Token string
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+var _ artifact.Checker = (*Client)(nil)
+
+// NewClient returns a client for registryURL (DefaultRegistryURL when empty).
+func NewClient(registryURL string) *Client {
+	if registryURL == "" {
+		registryURL = DefaultRegistryURL
+	}
+	return &Client{RegistryURL: registryURL, HTTPClient: http.DefaultClient}
+}
+
+// Check returns true if the package (e.g. my-package or @scope/my-package) exists on the npm registry.
+// If version is non-empty, returns true only when that specific version is published. Returns false and nil on 404.
+func Check(ctx context.Context, name, version string) (bool, error) {
+	return NewClient("").Check(ctx, name, version)
+}
+
+// Registry returns the registry host.
+func (c *Client) Registry() string {
+	return artifact.Host(c.RegistryURL)
+}
+
+// Check returns true if the package exists (see the package-level Check). The abbreviated packument
+// (GET /<name>) lists every published version.
+func (c *Client) Check(ctx context.Context, name, version string) (bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false, fmt.Errorf("package name is required")
+	}
+	// Scoped packages keep the @ but escape the slash: @scope%2fname.
+	u := strings.TrimSuffix(c.RegistryURL, "/") + "/" + strings.Replace(url.PathEscape(name), "%40", "@", 1)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, &artifact.StatusError{Host: c.Registry(), StatusCode: resp.StatusCode, Resource: name}
+	}
+	if version == "" {
+		return true, nil
+	}
+	var packument struct {
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&packument); err != nil {
+		return false, fmt.Errorf("parse npm metadata for %s: %w", name, err)
+	}
+	_, ok := packument.Versions[strings.TrimPrefix(strings.TrimSpace(version), "v")]
+	return ok, nil
+}
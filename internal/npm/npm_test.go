@@ -0,0 +1,42 @@
+package npm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/@acme%2Fwidget", "/@acme%2fwidget":
+			_, _ = w.Write([]byte(`{"name":"@acme/widget","versions":{"1.0.0":{},"1.1.0-rc.1":{}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	tests := []struct {
+		name, version string
+		want          bool
+	}{
+		{"@acme/widget", "", true},
+		{"@acme/widget", "1.0.0", true},
+		{"@acme/widget", "v1.1.0-rc.1", true},
+		{"@acme/widget", "1.2.0", false},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		got, err := c.Check(context.Background(), tt.name, tt.version)
+		if err != nil {
+			t.Errorf("Check(%s, %s): %v", tt.name, tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Check(%s, %s) = %v, want %v", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
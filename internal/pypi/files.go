@@ -0,0 +1,186 @@
+package pypi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// VerifyOptions describes the distribution files a published version must provide beyond existing.
+type VerifyOptions struct {
+	// Files lists the expected distributions: "sdist" (any source distribution), "wheel" (any wheel), or
+	// wheel tags separated by dashes (e.g. cp311-manylinux_x86_64, py3-none-any, cp312-win_amd64). Each tag
+	// must match one of a wheel's python, abi or platform tags; glob patterns are allowed (cp3*-macosx_*), and
+	// platform versions may be omitted (manylinux_x86_64 matches manylinux_2_17_x86_64 and manylinux2014_x86_64).
+	Files []string
+}
+
+// IsZero returns true if there is nothing to verify.
+func (o VerifyOptions) IsZero() bool {
+	return len(o.Files) == 0
+}
+
+// versionJSON is the subset of the JSON API version page (/pypi/<name>/<version>/json) that we read.
+type versionJSON struct {
+	URLs []struct {
+		Filename string `json:"filename"`
+	} `json:"urls"`
+}
+
+// Files returns the distribution filenames published for version: the urls of the JSON API version page,
+// or the version's files on the simple API when the index has no JSON API. Returns nil and nil when the
+// version is not published.
+func (c *Client) Files(ctx context.Context, name, version string) ([]string, error) {
+	name = strings.TrimSpace(name)
+	version = strings.TrimSpace(version)
+	if name == "" || version == "" {
+		return nil, fmt.Errorf("package name and version are required")
+	}
+	base, err := url.Parse(c.Index.jsonBaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("index url: %w", err)
+	}
+	u := base.JoinPath("pypi", name, version, "json")
+	resp, err := c.get(ctx, u.String(), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var v versionJSON
+		if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", u.Redacted(), err)
+		}
+		var files []string
+		for _, f := range v.URLs {
+			files = append(files, f.Filename)
+		}
+		return files, nil
+	case http.StatusNotFound:
+	default:
+		return nil, &artifact.StatusError{Host: c.Index.Host(), StatusCode: resp.StatusCode, Resource: u.Redacted()}
+	}
+
+	project, ok, err := c.simpleProject(ctx, name)
+	if err != nil || !ok {
+		return nil, err
+	}
+	want := normalizeVersion(version)
+	var files []string
+	for _, f := range project.Files {
+		if normalizeVersion(FileVersion(f.Filename)) == want {
+			files = append(files, f.Filename)
+		}
+	}
+	return files, nil
+}
+
+// Verify checks that every expected distribution in opts.Files is among the files published for version.
+// The error lists the distributions that are still missing.
+func (c *Client) Verify(ctx context.Context, name, version string, opts VerifyOptions) error {
+	if opts.IsZero() {
+		return nil
+	}
+	files, err := c.Files(ctx, name, version)
+	if err != nil {
+		return err
+	}
+	missing := MissingFiles(files, opts.Files)
+	if len(missing) == 0 {
+		return nil
+	}
+	return &MissingFilesError{Package: name, Version: version, Missing: missing, Files: files}
+}
+
+// MissingFilesError reports the expected distributions a version does not provide (yet).
+type MissingFilesError struct {
+	Package string
+	Version string
+	// Missing lists the expected distributions not matched by any file.
+	Missing []string
+	// Files are the distribution filenames published for the version.
+	Files []string
+}
+
+func (e *MissingFilesError) Error() string {
+	has := "no files"
+	if len(e.Files) > 0 {
+		sorted := append([]string(nil), e.Files...)
+		sort.Strings(sorted)
+		has = strings.Join(sorted, ", ")
+	}
+	return fmt.Sprintf("package %s==%s: missing distribution(s) %s (has %s)", e.Package, e.Version, strings.Join(e.Missing, ", "), has)
+}
+
+// MissingFiles returns the expected distributions (see VerifyOptions.Files) not matched by any filename.
+func MissingFiles(filenames, expected []string) []string {
+	var missing []string
+	for _, want := range expected {
+		found := false
+		for _, f := range filenames {
+			if fileMatches(f, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, want)
+		}
+	}
+	return missing
+}
+
+// fileMatches returns true if the distribution filename satisfies the expected distribution want.
+func fileMatches(filename, want string) bool {
+	want = strings.TrimSpace(want)
+	isWheel := strings.HasSuffix(filename, ".whl")
+	switch want {
+	case "sdist":
+		return !isWheel && FileVersion(filename) != ""
+	case "wheel":
+		return isWheel
+	}
+	if !isWheel {
+		return false
+	}
+	// name-version[-build]-python-abi-platform.whl: the last three fields are the (dot-compressed) tags.
+	parts := strings.Split(strings.TrimSuffix(filename, ".whl"), "-")
+	if len(parts) < 5 {
+		return false
+	}
+	var tags []string
+	for _, field := range parts[len(parts)-3:] {
+		tags = append(tags, strings.Split(field, ".")...)
+	}
+	for _, p := range strings.Split(want, "-") {
+		if !tagMatches(tags, strings.ToLower(p)) {
+			return false
+		}
+	}
+	return true
+}
+
+// platformVersionRegex matches the version in a platform tag (manylinux_2_17_, manylinux2014_, macosx_11_0_).
+var platformVersionRegex = regexp.MustCompile(`^(manylinux|musllinux|macosx)(?:_?\d+)*_`)
+
+// tagMatches returns true if pattern matches one of tags, directly or without the tag's platform version.
+func tagMatches(tags []string, pattern string) bool {
+	for _, t := range tags {
+		t = strings.ToLower(t)
+		for _, candidate := range []string{t, platformVersionRegex.ReplaceAllString(t, "${1}_")} {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
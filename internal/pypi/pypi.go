@@ -2,41 +2,110 @@ package pypi
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+const (
+	// DefaultIndexURL is the simple index URL of pypi.org.
+	DefaultIndexURL = "https://pypi.org/simple/"
+	// TestPyPIIndexURL is the simple index URL of test.pypi.org.
+	TestPyPIIndexURL = "https://test.pypi.org/simple/"
 )
 
-const defaultBaseURL = "https://pypi.org"
+// simpleJSONAccept requests the PEP 691 JSON form of the simple API, with HTML (PEP 503) as a fallback.
+const simpleJSONAccept = "application/vnd.pypi.simple.v1+json, text/html;q=0.1"
+
+// Index is a Python package index (pypi.org, TestPyPI, Artifactory, devpi, ...).
+type Index struct {
+	// URL is the index URL as given to pip --index-url (e.g. https://pypi.org/simple/). The JSON API
+	// (/pypi/<name>/<version>/json) is expected at the same URL without the trailing /simple.
+	URL string
+	// Username and Password are sent with basic auth when Username is set.
+	Username string
+	Password string
+	// Token is sent as a bearer token when set (and Username is empty).
+	Token string
+}
+
+// Host returns the index host for messages (e.g. "pypi.org").
+func (i Index) Host() string {
+	if u, err := url.Parse(i.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return i.URL
+}
+
+// simpleURL returns the simple API base URL with a trailing slash.
+func (i Index) simpleURL() string {
+	return strings.TrimSuffix(i.URL, "/") + "/"
+}
+
+// jsonBaseURL returns the JSON API base: the index URL without its trailing /simple.
+func (i Index) jsonBaseURL() string {
+	return strings.TrimSuffix(strings.TrimSuffix(i.URL, "/"), "/simple")
+}
+
+// Client checks packages on a single index.
+type Client struct {
+	Index Index
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for index (DefaultIndexURL when index.URL is empty).
+func NewClient(index Index) *Client {
+	if index.URL == "" {
+		index.URL = DefaultIndexURL
+	}
+	return &Client{Index: index, HTTPClient: http.DefaultClient}
+}
 
 // Check returns true if the package exists on PyPI. If version is non-empty,
-// returns true only when that specific version is published (200 from
-// /pypi/<name>/<version>/json). Otherwise checks /pypi/<name>/json.
-// Returns false and nil on 404.
+// returns true only when that specific version is published. Returns false and nil on 404.
 func Check(ctx context.Context, name, version string) (bool, error) {
+	return NewClient(Index{}).Check(ctx, name, version)
+}
+
+// Check returns true if the package exists on the index. If version is non-empty, returns true only when
+// that specific version is published. The JSON API (/pypi/<name>[/<version>]/json) is tried first; when it
+// reports 404 (or the index has no JSON API), the PEP 691 simple JSON API is consulted instead.
+// Returns false and nil when neither lists the package (version).
+func (c *Client) Check(ctx context.Context, name, version string) (bool, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return false, fmt.Errorf("package name is required")
 	}
-	base, err := url.Parse(defaultBaseURL)
+	version = strings.TrimSpace(version)
+	ok, err := c.checkJSON(ctx, name, version)
+	if err != nil || ok {
+		return ok, err
+	}
+	return c.checkSimple(ctx, name, version)
+}
+
+// checkJSON checks the JSON API. A 404 (missing package/version, or no JSON API) returns false and nil.
+func (c *Client) checkJSON(ctx context.Context, name, version string) (bool, error) {
+	base, err := url.Parse(c.Index.jsonBaseURL())
 	if err != nil {
-		return false, err
+		return false, fmt.Errorf("index url: %w", err)
 	}
 	var u *url.URL
 	if version != "" {
-		version = strings.TrimSpace(version)
 		u = base.JoinPath("pypi", name, version, "json")
 	} else {
 		u = base.JoinPath("pypi", name, "json")
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		return false, err
-	}
-	req.Header.Set("Accept", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.get(ctx, u.String(), "application/json")
 	if err != nil {
 		return false, err
 	}
@@ -47,16 +116,153 @@ func Check(ctx context.Context, name, version string) (bool, error) {
 	case http.StatusNotFound:
 		return false, nil
 	default:
-		return false, fmt.Errorf("pypi returned %d for %s", resp.StatusCode, u.Redacted())
+		return false, &artifact.StatusError{Host: c.Index.Host(), StatusCode: resp.StatusCode, Resource: u.Redacted()}
+	}
+}
+
+// simpleProject is the PEP 691 JSON project page (versions is PEP 700 and may be absent).
+type simpleProject struct {
+	Versions []string     `json:"versions"`
+	Files    []simpleFile `json:"files"`
+}
+
+// simpleFile is one distribution file on a simple API project page.
+type simpleFile struct {
+	Filename string `json:"filename"`
+}
+
+// anchorTextRegex matches the link text of a PEP 503 HTML project page.
+var anchorTextRegex = regexp.MustCompile(`<a[^>]*>([^<]+)</a>`)
+
+// checkSimple checks the simple API project page for the package, and for version among its
+// versions or distribution filenames.
+func (c *Client) checkSimple(ctx context.Context, name, version string) (bool, error) {
+	project, ok, err := c.simpleProject(ctx, name)
+	if err != nil || !ok || version == "" {
+		return ok, err
+	}
+	want := normalizeVersion(version)
+	for _, v := range project.Versions {
+		if normalizeVersion(v) == want {
+			return true, nil
+		}
+	}
+	for _, f := range project.Files {
+		if normalizeVersion(FileVersion(f.Filename)) == want {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// simpleProject fetches the simple API project page (PEP 691 JSON, or PEP 503 HTML whose link texts become
+// the files). ok is false when the index has no such project.
+func (c *Client) simpleProject(ctx context.Context, name string) (*simpleProject, bool, error) {
+	u := c.Index.simpleURL() + NormalizeName(name) + "/"
+	resp, err := c.get(ctx, u, simpleJSONAccept)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, &artifact.StatusError{Host: c.Index.Host(), StatusCode: resp.StatusCode, Resource: redact(u)}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	var project simpleProject
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		if err := json.Unmarshal(body, &project); err != nil {
+			return nil, false, fmt.Errorf("parse simple index page for %s: %w", name, err)
+		}
+	} else {
+		for _, m := range anchorTextRegex.FindAllStringSubmatch(string(body), -1) {
+			project.Files = append(project.Files, simpleFile{Filename: strings.TrimSpace(m[1])})
+		}
+	}
+	return &project, true, nil
+}
+
+// get sends a GET with the index credentials.
+func (c *Client) get(ctx context.Context, u, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if c.Index.Username != "" {
+		req.SetBasicAuth(c.Index.Username, c.Index.Password)
+	} else if c.Index.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Index.Token)
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return hc.Do(req)
+}
+
+// nameSeparatorRegex matches runs of the characters PEP 503 treats as equivalent in project names.
+var nameSeparatorRegex = regexp.MustCompile(`[-_.]+`)
+
+// NormalizeName returns the PEP 503 normalized project name (lowercase, runs of -_. replaced by -).
+func NormalizeName(name string) string {
+	return strings.ToLower(nameSeparatorRegex.ReplaceAllString(name, "-"))
+}
+
+// FileVersion returns the version in a distribution filename: the second dash-separated field of a wheel
+// (name-1.2.3-py3-none-any.whl) or the part after the last dash of an sdist (name-1.2.3.tar.gz).
+// Returns "" when the filename is not a recognized distribution.
+func FileVersion(filename string) string {
+	if strings.HasSuffix(filename, ".whl") {
+		parts := strings.Split(strings.TrimSuffix(filename, ".whl"), "-")
+		if len(parts) < 5 {
+			return ""
+		}
+		return parts[1]
 	}
+	for _, ext := range []string{".tar.gz", ".zip", ".tar.bz2", ".tgz"} {
+		if strings.HasSuffix(filename, ext) {
+			base := strings.TrimSuffix(filename, ext)
+			if i := strings.LastIndex(base, "-"); i >= 0 {
+				return base[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeVersion lowercases v and drops a leading "v" for comparison.
+func normalizeVersion(v string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "v")
+}
+
+// redact removes userinfo from u for messages.
+func redact(u string) string {
+	if parsed, err := url.Parse(u); err == nil {
+		return parsed.Redacted()
+	}
+	return u
 }
 
 // WaitOptions configures Wait behavior.
 type WaitOptions struct {
 	// Timeout is the maximum time to wait for the package/version to appear (default 5m).
 	Timeout time.Duration
-	// Interval is how often to poll (default 5s).
+	// Interval is the delay before the second poll (default 5s); later delays back off up to MaxInterval.
 	Interval time.Duration
+	// MaxInterval caps the delay between polls (default 30s).
+	MaxInterval time.Duration
+	// Verify, when set, must also pass (see Client.Verify); the version is polled until it does, since CI may
+	// upload wheels for each platform minutes after the first file makes the version visible.
+	Verify VerifyOptions
+	// Progress, when set, is called after every poll that did not succeed.
+	Progress func(artifact.Progress)
 }
 
 // DefaultWaitOptions returns defaults: 5m timeout, 5s interval.
@@ -67,38 +273,38 @@ func DefaultWaitOptions() WaitOptions {
 	}
 }
 
-// Wait polls PyPI until the package (and optionally version) exists or the context/timeout is exceeded.
+// Wait polls PyPI until the package (and optionally version) exists, and has the distribution files in
+// opts.Verify, or the context/timeout is exceeded. Server and network errors are retried (see artifact.Poll).
 // Returns nil when the package is available; returns an error on timeout or other failure.
 func Wait(ctx context.Context, name, version string, opts WaitOptions) error {
-	if opts.Timeout == 0 {
-		opts.Timeout = 5 * time.Minute
-	}
-	if opts.Interval == 0 {
-		opts.Interval = 5 * time.Second
-	}
-	deadline := time.Now().Add(opts.Timeout)
-	ticker := time.NewTicker(opts.Interval)
-	defer ticker.Stop()
-	for {
-		ok, err := Check(ctx, name, version)
-		if err != nil {
-			return err
-		}
-		if ok {
-			return nil
+	return NewClient(Index{}).Wait(ctx, name, version, opts)
+}
+
+// Registry returns the index host; with Check it makes Client an artifact.Checker.
+func (c *Client) Registry() string {
+	return c.Index.Host()
+}
+
+// Wait polls the index until the package (and optionally version) exists (see the package-level Wait).
+func (c *Client) Wait(ctx context.Context, name, version string, opts WaitOptions) error {
+	ref := name
+	if version != "" {
+		ref = name + "==" + version
+	}
+	pollOpts := artifact.WaitOptions{Timeout: opts.Timeout, Interval: opts.Interval, MaxInterval: opts.MaxInterval, Progress: opts.Progress}
+	return artifact.Poll(ctx, pollOpts, func(ctx context.Context) (bool, string, error) {
+		ok, err := c.Check(ctx, name, version)
+		if err != nil || !ok {
+			return false, fmt.Sprintf("package %s not available on %s", ref, c.Index.Host()), err
 		}
-		if time.Now().After(deadline) {
-			ref := name
-			if version != "" {
-				ref = name + "==" + version
-			}
-			return fmt.Errorf("package %s not available on PyPI after %v", ref, opts.Timeout)
+		if version == "" || opts.Verify.IsZero() {
+			return true, "", nil
 		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
-			continue
+		err = c.Verify(ctx, name, version, opts.Verify)
+		var missing *MissingFilesError
+		if errors.As(err, &missing) {
+			return false, err.Error(), nil
 		}
-	}
+		return err == nil, "", err
+	})
 }
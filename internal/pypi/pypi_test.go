@@ -0,0 +1,146 @@
+package pypi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck_SimpleFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "ci" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/pypi/local/simple/my-package/":
+			w.Header().Set("Content-Type", "application/vnd.pypi.simple.v1+json")
+			_, _ = w.Write([]byte(`{"files":[{"filename":"my_package-1.2.3-py3-none-any.whl"},{"filename":"my_package-1.2.3.tar.gz"}]}`))
+		case "/api/pypi/local/simple/html-only/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><body><a href="../x/html_only-0.9.0.tar.gz#sha256=00">html_only-0.9.0.tar.gz</a></body></html>`))
+		default:
+			// No JSON API on this index.
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(Index{URL: srv.URL + "/api/pypi/local/simple", Username: "ci", Password: "secret"})
+	tests := []struct {
+		name, version string
+		want          bool
+	}{
+		{"My_Package", "1.2.3", true},
+		{"my-package", "1.2.4", false},
+		{"my-package", "", true},
+		{"html-only", "0.9.0", true},
+		{"missing", "", false},
+	}
+	for _, tt := range tests {
+		got, err := c.Check(context.Background(), tt.name, tt.version)
+		if err != nil {
+			t.Errorf("Check(%s, %s): %v", tt.name, tt.version, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Check(%s, %s) = %v, want %v", tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestCheck_JSONAPIWithToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/pypi/pkg/1.0.0/json" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	ok, err := NewClient(Index{URL: srv.URL + "/simple/", Token: "tok"}).Check(context.Background(), "pkg", "1.0.0")
+	if err != nil || !ok {
+		t.Fatalf("Check = %v, %v; want true", ok, err)
+	}
+}
+
+func TestFileVersion(t *testing.T) {
+	tests := map[string]string{
+		"my_package-1.2.3-py3-none-any.whl":                           "1.2.3",
+		"my_package-1.2.3-cp311-cp311-manylinux_2_17_x86_64.whl":      "1.2.3",
+		"my_package-1.2.3rc1-1-cp311-cp311-manylinux_2_17_x86_64.whl": "1.2.3rc1",
+		"my-package-1.2.3.tar.gz":                                     "1.2.3",
+		"README.txt":                                                  "",
+	}
+	for filename, want := range tests {
+		if got := FileVersion(filename); got != want {
+			t.Errorf("FileVersion(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+func TestMissingFiles(t *testing.T) {
+	files := []string{
+		"my_package-1.2.3.tar.gz",
+		"my_package-1.2.3-cp311-cp311-manylinux_2_17_x86_64.manylinux2014_x86_64.whl",
+		"my_package-1.2.3-cp311-cp311-macosx_11_0_arm64.whl",
+	}
+	expected := []string{
+		"sdist",
+		"wheel",
+		"cp311-manylinux_x86_64",
+		"cp311-manylinux_2_17_x86_64",
+		"cp311-macosx_arm64",
+		"cp3*-macosx_*",
+		"cp312-manylinux_x86_64",
+		"cp311-win_amd64",
+		"py3-none-any",
+	}
+	got := MissingFiles(files, expected)
+	want := []string{"cp312-manylinux_x86_64", "cp311-win_amd64", "py3-none-any"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("MissingFiles = %v, want %v", got, want)
+	}
+	if got := MissingFiles([]string{"my_package-1.2.3-py3-none-any.whl"}, []string{"sdist"}); len(got) != 1 {
+		t.Errorf("MissingFiles(wheel only, sdist) = %v, want [sdist]", got)
+	}
+}
+
+func TestWait_VerifyFiles(t *testing.T) {
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pypi/pkg/1.0.0/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		polls++
+		urls := `{"filename":"pkg-1.0.0.tar.gz"}`
+		if polls > 2 {
+			urls += `,{"filename":"pkg-1.0.0-cp311-cp311-manylinux_2_17_x86_64.whl"}`
+		}
+		_, _ = w.Write([]byte(`{"urls":[` + urls + `]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(Index{URL: srv.URL + "/simple/"})
+	opts := WaitOptions{Timeout: time.Second, Interval: 10 * time.Millisecond, Verify: VerifyOptions{Files: []string{"sdist", "cp311-manylinux_x86_64"}}}
+	if err := c.Wait(context.Background(), "pkg", "1.0.0", opts); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+
+	opts.Timeout = 50 * time.Millisecond
+	opts.Verify.Files = []string{"cp312-manylinux_x86_64"}
+	err := c.Wait(context.Background(), "pkg", "1.0.0", opts)
+	if err == nil || !strings.Contains(err.Error(), "missing distribution(s) cp312-manylinux_x86_64") {
+		t.Fatalf("Wait error = %v, want missing cp312-manylinux_x86_64", err)
+	}
+}
@@ -0,0 +1,133 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials are the username and password (or token) used to authenticate with a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// CredentialStore returns the credentials for a registry host. ok is false when none are configured.
+type CredentialStore interface {
+	Get(ctx context.Context, registry string) (creds Credentials, ok bool, err error)
+}
+
+// dockerHubConfigKey is the key docker login uses for Docker Hub in config.json.
+const dockerHubConfigKey = "https://index.docker.io/v1/"
+
+// DockerConfig is the subset of ~/.docker/config.json used for registry credentials.
+type DockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+	// CredsStore is the default credential helper (docker-credential-<name>).
+	CredsStore string `json:"credsStore"`
+	// CredHelpers maps registry hosts to credential helpers, overriding CredsStore.
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// LoadDockerConfig reads $DOCKER_CONFIG/config.json (default ~/.docker/config.json).
+// A missing file yields an empty config and no error.
+func LoadDockerConfig() (*DockerConfig, error) {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return &DockerConfig{}, nil
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &DockerConfig{}, nil
+		}
+		return nil, fmt.Errorf("read docker config: %w", err)
+	}
+	var c DockerConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse docker config: %w", err)
+	}
+	return &c, nil
+}
+
+// Get returns the credentials for registry: from its credential helper (credHelpers, then credsStore),
+// else from auths (basic auth, as written by docker login).
+func (c *DockerConfig) Get(ctx context.Context, registry string) (Credentials, bool, error) {
+	key := registry
+	if registry == DockerHub {
+		key = dockerHubConfigKey
+	}
+	helper := c.CredHelpers[registry]
+	if helper == "" {
+		helper = c.CredsStore
+	}
+	if helper != "" {
+		creds, ok, err := helperCredentials(ctx, helper, key)
+		if err != nil || ok {
+			return creds, ok, err
+		}
+	}
+	for _, k := range []string{key, "https://" + registry, "http://" + registry} {
+		a, found := c.Auths[k]
+		if !found {
+			continue
+		}
+		if a.Auth != "" {
+			raw, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return Credentials{}, false, fmt.Errorf("docker config auth for %s: %w", registry, err)
+			}
+			user, pass, ok := strings.Cut(string(raw), ":")
+			if !ok {
+				return Credentials{}, false, fmt.Errorf("docker config auth for %s: expected user:password", registry)
+			}
+			return Credentials{Username: user, Password: pass}, true, nil
+		}
+		if a.Username != "" {
+			return Credentials{Username: a.Username, Password: a.Password}, true, nil
+		}
+	}
+	return Credentials{}, false, nil
+}
+
+// helperCredentials runs "docker-credential-<helper> get" for serverURL. A helper reporting that it has no
+// credentials for the server is not an error.
+func helperCredentials(ctx context.Context, helper, serverURL string) (Credentials, bool, error) {
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(out.String() + stderr.String())
+		if strings.Contains(msg, "credentials not found") {
+			return Credentials{}, false, nil
+		}
+		return Credentials{}, false, fmt.Errorf("credential helper %s: %w (%s)", helper, err, msg)
+	}
+	var resp struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		return Credentials{}, false, fmt.Errorf("credential helper %s: %w", helper, err)
+	}
+	if resp.Secret == "" {
+		return Credentials{}, false, nil
+	}
+	return Credentials{Username: resp.Username, Password: resp.Secret}, true, nil
+}
@@ -0,0 +1,203 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// VerifyOptions describes what a released image must provide beyond existing.
+type VerifyOptions struct {
+	// Platforms lists the required platforms as os/arch[/variant] (e.g. linux/amd64, linux/arm64/v8).
+	// A variant is only compared when given. Empty means any platform.
+	Platforms []string
+	// Labels are image config labels that must have the given values (e.g. org.opencontainers.image.version).
+	// Values match with or without a leading "v" (1.2.3 matches v1.2.3). Checked for every required platform.
+	Labels map[string]string
+}
+
+// IsZero returns true if there is nothing to verify.
+func (o VerifyOptions) IsZero() bool {
+	return len(o.Platforms) == 0 && len(o.Labels) == 0
+}
+
+// Platform is an image platform (from an index entry or an image config).
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// String returns os/arch[/variant].
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// matches returns true if p satisfies the required platform want (os/arch[/variant]).
+func (p Platform) matches(want string) bool {
+	parts := strings.Split(want, "/")
+	if len(parts) < 2 || parts[0] != p.OS || parts[1] != p.Architecture {
+		return false
+	}
+	return len(parts) < 3 || parts[2] == p.Variant
+}
+
+// manifest is the subset of an OCI image index / Docker manifest list, or an image manifest, that we read.
+type manifest struct {
+	Manifests []struct {
+		Digest   string    `json:"digest"`
+		Platform *Platform `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// imageConfig is the subset of an image config blob that we read.
+type imageConfig struct {
+	Platform
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// Verify checks that the image (which must exist) provides every platform in opts.Platforms, and that each
+// of those platforms' image config has opts.Labels. A single-platform manifest is checked against its config.
+func (c *Client) Verify(ctx context.Context, image string, opts VerifyOptions) error {
+	if opts.IsZero() {
+		return nil
+	}
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+	m, err := c.fetchManifest(ctx, ref, ref.manifestRef())
+	if err != nil {
+		return err
+	}
+
+	// images lists each platform with its manifest digest ("" for a single-platform manifest already fetched).
+	type platformImage struct {
+		platform Platform
+		digest   string
+	}
+	var images []platformImage
+	if len(m.Manifests) > 0 {
+		for _, d := range m.Manifests {
+			if d.Platform == nil || d.Platform.OS == "unknown" {
+				continue // attestation manifests
+			}
+			images = append(images, platformImage{*d.Platform, d.Digest})
+		}
+	} else {
+		cfg, err := c.fetchConfig(ctx, ref, m)
+		if err != nil {
+			return err
+		}
+		images = append(images, platformImage{platform: cfg.Platform})
+	}
+
+	var missing, found []string
+	selected := images
+	if len(opts.Platforms) > 0 {
+		selected = nil
+		for _, want := range opts.Platforms {
+			ok := false
+			for _, img := range images {
+				if img.platform.matches(want) {
+					selected = append(selected, img)
+					ok = true
+					break
+				}
+			}
+			if !ok {
+				missing = append(missing, want)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		for _, img := range images {
+			found = append(found, img.platform.String())
+		}
+		sort.Strings(found)
+		return fmt.Errorf("image %s: missing platform(s) %s (has %s)", image, strings.Join(missing, ", "), strings.Join(found, ", "))
+	}
+
+	if len(opts.Labels) == 0 {
+		return nil
+	}
+	for _, img := range selected {
+		pm := m
+		if img.digest != "" {
+			if pm, err = c.fetchManifest(ctx, ref, img.digest); err != nil {
+				return err
+			}
+		}
+		cfg, err := c.fetchConfig(ctx, ref, pm)
+		if err != nil {
+			return err
+		}
+		for label, want := range opts.Labels {
+			got, ok := cfg.Config.Labels[label]
+			if !ok {
+				return fmt.Errorf("image %s (%s): label %s not set", image, img.platform, label)
+			}
+			if strings.TrimPrefix(got, "v") != strings.TrimPrefix(want, "v") {
+				return fmt.Errorf("image %s (%s): label %s = %q, want %q", image, img.platform, label, got, want)
+			}
+		}
+	}
+	return nil
+}
+
+// fetchManifest GETs the manifest (or index) for reference (tag or digest) in ref's repository.
+func (c *Client) fetchManifest(ctx context.Context, ref Reference, reference string) (*manifest, error) {
+	data, err := c.get(ctx, ref, "/manifests/"+reference, manifestAccept)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", reference, err)
+	}
+	return &m, nil
+}
+
+// fetchConfig GETs the image config blob referenced by an image manifest.
+func (c *Client) fetchConfig(ctx context.Context, ref Reference, m *manifest) (*imageConfig, error) {
+	if m.Config.Digest == "" {
+		return nil, fmt.Errorf("image %s: manifest has no config", ref)
+	}
+	data, err := c.get(ctx, ref, "/blobs/"+m.Config.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	var cfg imageConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse image config %s: %w", m.Config.Digest, err)
+	}
+	return &cfg, nil
+}
+
+// get GETs path under ref's repository and returns the body; any status other than 200 is an error.
+func (c *Client) get(ctx context.Context, ref Reference, path, accept string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, ref, path, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &artifact.StatusError{Host: ref.Registry, StatusCode: resp.StatusCode, Resource: ref.Repository + path}
+	}
+	return io.ReadAll(resp.Body)
+}
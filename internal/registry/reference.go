@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DockerHub is the registry name used for references without a registry host (e.g. "nginx:latest").
+	DockerHub = "docker.io"
+	// dockerHubAPIHost is the host serving the Docker Hub registry API.
+	dockerHubAPIHost = "registry-1.docker.io"
+)
+
+// Reference is a parsed image reference: [host[:port]/]repository[:tag][@digest].
+type Reference struct {
+	// Registry is the registry host (and port), e.g. "ghcr.io" or "localhost:5000"; DockerHub when omitted.
+	Registry string
+	// Repository is the repository path, e.g. "myorg/myimage" ("library/nginx" for official Docker Hub images).
+	Repository string
+	// Tag is the tag ("latest" when neither tag nor digest is given).
+	Tag string
+	// Digest is the manifest digest (e.g. "sha256:..."); it takes precedence over Tag.
+	Digest string
+}
+
+// ParseReference parses an image reference such as "nginx", "myorg/myimage:v1.0", "ghcr.io/org/app:v1.2.3",
+// "localhost:5000/app@sha256:..." or "registry.example.com:8443/team/app:1.0". The first path component is
+// the registry host when it contains a "." or ":" or is "localhost"; otherwise the image is on Docker Hub.
+func ParseReference(image string) (Reference, error) {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return Reference{}, fmt.Errorf("empty image reference")
+	}
+	var ref Reference
+	if i := strings.Index(image, "@"); i >= 0 {
+		ref.Digest = image[i+1:]
+		image = image[:i]
+		if !strings.Contains(ref.Digest, ":") {
+			return Reference{}, fmt.Errorf("invalid digest in image reference %q", image+"@"+ref.Digest)
+		}
+	}
+	// A tag follows the last colon only if no "/" comes after it (otherwise the colon is a registry port).
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i+1:], "/") {
+		ref.Tag = image[i+1:]
+		image = image[:i]
+		if ref.Tag == "" {
+			return Reference{}, fmt.Errorf("empty tag in image reference %q", image+":")
+		}
+	}
+	ref.Registry = DockerHub
+	if i := strings.Index(image, "/"); i >= 0 {
+		first := image[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Registry = first
+			image = image[i+1:]
+		}
+	}
+	if ref.Registry == "index.docker.io" || ref.Registry == dockerHubAPIHost {
+		ref.Registry = DockerHub
+	}
+	if ref.Registry == DockerHub && !strings.Contains(image, "/") {
+		image = "library/" + image
+	}
+	if image == "" {
+		return Reference{}, fmt.Errorf("missing repository in image reference")
+	}
+	ref.Repository = image
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	return ref, nil
+}
+
+// String returns the reference in canonical form (registry/repository:tag or registry/repository@digest).
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Digest != "" {
+		return s + "@" + r.Digest
+	}
+	return s + ":" + r.Tag
+}
+
+// manifestRef returns the digest, or the tag when there is no digest.
+func (r Reference) manifestRef() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// apiHost returns the host serving the registry API.
+func (r Reference) apiHost() string {
+	if r.Registry == DockerHub {
+		return dockerHubAPIHost
+	}
+	return r.Registry
+}
+
+// apiScheme returns "http" for loopback registries (as docker does for insecure local registries), else "https".
+func (r Reference) apiScheme() string {
+	host := r.Registry
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	if host == "localhost" || host == "127.0.0.1" || host == "[::1]" {
+		return "http"
+	}
+	return "https"
+}
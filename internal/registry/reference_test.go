@@ -0,0 +1,45 @@
+package registry
+
+import "testing"
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{"nginx", Reference{Registry: DockerHub, Repository: "library/nginx", Tag: "latest"}},
+		{"myorg/myimage:v1.0", Reference{Registry: DockerHub, Repository: "myorg/myimage", Tag: "v1.0"}},
+		{"docker.io/nginx:1.25", Reference{Registry: DockerHub, Repository: "library/nginx", Tag: "1.25"}},
+		{"ghcr.io/org/app:v1.2.3", Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1.2.3"}},
+		{"localhost:5000/app", Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{"registry.example.com:8443/team/app:1.0", Reference{Registry: "registry.example.com:8443", Repository: "team/app", Tag: "1.0"}},
+		{"ghcr.io/org/app@sha256:abc", Reference{Registry: "ghcr.io", Repository: "org/app", Digest: "sha256:abc"}},
+		{"ghcr.io/org/app:v1@sha256:abc", Reference{Registry: "ghcr.io", Repository: "org/app", Tag: "v1", Digest: "sha256:abc"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.image)
+		if err != nil {
+			t.Errorf("ParseReference(%q): %v", tt.image, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "app:", "ghcr.io/org/app@abc"} {
+		if _, err := ParseReference(bad); err == nil {
+			t.Errorf("ParseReference(%q): expected error", bad)
+		}
+	}
+}
+
+func TestReferenceAPI(t *testing.T) {
+	ref, _ := ParseReference("nginx")
+	if ref.apiHost() != "registry-1.docker.io" || ref.apiScheme() != "https" {
+		t.Errorf("docker hub api = %s://%s", ref.apiScheme(), ref.apiHost())
+	}
+	ref, _ = ParseReference("localhost:5000/app")
+	if ref.apiHost() != "localhost:5000" || ref.apiScheme() != "http" {
+		t.Errorf("local api = %s://%s", ref.apiScheme(), ref.apiHost())
+	}
+}
@@ -0,0 +1,285 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnewart/releasebot/internal/artifact"
+)
+
+// manifestAccept lists the manifest media types we accept: OCI index/manifest and Docker manifest list/v2.
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// Client talks to OCI distribution (Docker Registry v2) APIs. It follows the WWW-Authenticate challenge
+// of each registry (bearer token or basic auth), using Credentials when the registry requires them.
+type Client struct {
+	// HTTPClient is used for all requests (default http.DefaultClient).
+	HTTPClient *http.Client
+	// Credentials supplies registry credentials (nil = anonymous only).
+	Credentials CredentialStore
+
+	mu     sync.Mutex
+	tokens map[string]string // "registry/repository" → bearer token
+}
+
+// NewClient returns a client using credentials from the docker config (~/.docker/config.json).
+// An unreadable docker config is ignored so public images can still be checked.
+func NewClient() *Client {
+	c := &Client{HTTPClient: http.DefaultClient}
+	if dc, err := LoadDockerConfig(); err == nil {
+		c.Credentials = dc
+	}
+	return c
+}
+
+// Check returns true if the image (e.g. "nginx:latest", "ghcr.io/org/app:v1.0", "localhost:5000/app:1.0")
+// exists in its registry. It uses the Registry API v2 HEAD manifest endpoint. Returns false and nil on 404,
+// and when the registry denies pull access (private or missing repository).
+func Check(ctx context.Context, image string) (bool, error) {
+	return NewClient().Check(ctx, image)
+}
+
+// Check returns true if the image exists in its registry (see the package-level Check).
+func (c *Client) Check(ctx context.Context, image string) (bool, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.do(ctx, http.MethodHead, ref, "/manifests/"+ref.manifestRef(), manifestAccept)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		// 404 = unknown manifest; 401/403 = no pull access (private or missing)
+		return false, nil
+	default:
+		return false, &artifact.StatusError{Host: ref.Registry, StatusCode: resp.StatusCode, Resource: "manifest " + ref.String()}
+	}
+}
+
+// WaitOptions configures Wait behavior.
+type WaitOptions struct {
+	// Timeout is the maximum time to wait for the image to appear (default 5m).
+	Timeout time.Duration
+	// Interval is the delay before the second poll (default 5s); later delays back off up to MaxInterval.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls (default 30s).
+	MaxInterval time.Duration
+	// Verify, when set, must also pass (see Client.Verify); the image is polled until it does, since a
+	// multi-arch push may publish the tag before every platform is in the index.
+	Verify VerifyOptions
+	// Progress, when set, is called after every poll that did not succeed.
+	Progress func(artifact.Progress)
+}
+
+// DefaultWaitOptions returns defaults: 5m timeout, 5s interval.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Timeout:  5 * time.Minute,
+		Interval: 5 * time.Second,
+	}
+}
+
+// Wait polls the image's registry until the image exists (and passes opts.Verify) or the context/timeout is exceeded.
+// Server and network errors are retried (see artifact.Poll).
+// Returns nil when the image is available; returns an error on timeout or other failure.
+func Wait(ctx context.Context, image string, opts WaitOptions) error {
+	return NewClient().Wait(ctx, image, opts)
+}
+
+// Wait polls until the image exists (see the package-level Wait).
+func (c *Client) Wait(ctx context.Context, image string, opts WaitOptions) error {
+	pollOpts := artifact.WaitOptions{Timeout: opts.Timeout, Interval: opts.Interval, MaxInterval: opts.MaxInterval, Progress: opts.Progress}
+	return artifact.Poll(ctx, pollOpts, func(ctx context.Context) (bool, string, error) {
+		ok, err := c.Check(ctx, image)
+		if err != nil || !ok {
+			return false, fmt.Sprintf("image %s not available", image), err
+		}
+		// A failed verification (missing platform, label not set yet, manifest not pushed yet) is retried.
+		if err := c.Verify(ctx, image, opts.Verify); err != nil {
+			if artifact.IsTransient(err) {
+				return false, "", err
+			}
+			return false, err.Error(), nil
+		}
+		return true, "", nil
+	})
+}
+
+// do sends a request for path under /v2/<repository> and answers an authentication challenge once:
+// a Bearer challenge is exchanged for a token (cached per repository), a Basic challenge is answered with
+// the registry's credentials. The caller closes the response body.
+func (c *Client) do(ctx context.Context, method string, ref Reference, path, accept string) (*http.Response, error) {
+	u := ref.apiScheme() + "://" + ref.apiHost() + "/v2/" + ref.Repository + path
+	send := func(auth string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		return c.httpClient().Do(req)
+	}
+	key := ref.Registry + "/" + ref.Repository
+	c.mu.Lock()
+	token := c.tokens[key]
+	c.mu.Unlock()
+	auth := ""
+	if token != "" {
+		auth = "Bearer " + token
+	}
+	resp, err := send(auth)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	scheme, params := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch scheme {
+	case "bearer":
+		resp.Body.Close()
+		token, err := c.fetchToken(ctx, ref, params)
+		if err != nil {
+			return nil, fmt.Errorf("registry auth for %s: %w", ref.Registry, err)
+		}
+		c.mu.Lock()
+		if c.tokens == nil {
+			c.tokens = make(map[string]string)
+		}
+		c.tokens[key] = token
+		c.mu.Unlock()
+		return send("Bearer " + token)
+	case "basic":
+		creds, ok, err := c.credentials(ctx, ref.Registry)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		if ok {
+			resp.Body.Close()
+			return send("Basic " + base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password)))
+		}
+	}
+	// No way to authenticate: return the 401 (no pull access).
+	return resp, nil
+}
+
+// fetchToken gets a bearer token from the challenge's realm for pulling ref.Repository. Credentials, when
+// configured for the registry, are sent with basic auth; otherwise an anonymous token is requested.
+func (c *Client) fetchToken(ctx context.Context, ref Reference, params map[string]string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge without realm")
+	}
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("bearer realm: %w", err)
+	}
+	q := u.Query()
+	if s := params["service"]; s != "" {
+		q.Set("service", s)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	q.Set("scope", scope)
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	creds, ok, err := c.credentials(ctx, ref.Registry)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &artifact.StatusError{Host: u.Host, StatusCode: resp.StatusCode, Resource: "token"}
+	}
+	var tr struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return "", err
+	}
+	if tr.Token == "" {
+		tr.Token = tr.AccessToken
+	}
+	if tr.Token == "" {
+		return "", fmt.Errorf("empty token in auth response")
+	}
+	return tr.Token, nil
+}
+
+func (c *Client) credentials(ctx context.Context, registry string) (Credentials, bool, error) {
+	if c.Credentials == nil {
+		return Credentials{}, false, nil
+	}
+	return c.Credentials.Get(ctx, registry)
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`
+// into its lower-cased scheme and parameters. Quoted values may contain commas.
+func parseChallenge(header string) (scheme string, params map[string]string) {
+	header = strings.TrimSpace(header)
+	scheme, rest, _ := strings.Cut(header, " ")
+	params = make(map[string]string)
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimLeft(rest, ", ") {
+		key, after, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		var value string
+		if strings.HasPrefix(after, `"`) {
+			end := strings.Index(after[1:], `"`)
+			if end < 0 {
+				value, rest = after[1:], ""
+			} else {
+				value, rest = after[1:end+1], after[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(after, ",")
+		}
+		params[key] = value
+	}
+	return strings.ToLower(scheme), params
+}
@@ -0,0 +1,183 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type staticCreds map[string]Credentials
+
+func (s staticCreds) Get(ctx context.Context, registry string) (Credentials, bool, error) {
+	c, ok := s[registry]
+	return c, ok, nil
+}
+
+func TestCheck_BearerChallenge(t *testing.T) {
+	var srv *httptest.Server
+	tokenRequests := 0
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			if got := r.URL.Query().Get("scope"); got != "repository:org/app:pull" {
+				t.Errorf("scope = %q", got)
+			}
+			if user, pass, ok := r.BasicAuth(); !ok || user != "bob" || pass != "secret" {
+				t.Errorf("token request basic auth = %q %q %v", user, pass, ok)
+			}
+			_, _ = w.Write([]byte(`{"access_token":"tok"}`))
+		case "/v2/org/app/manifests/v1.0", "/v2/org/app/manifests/v2.0":
+			if r.Header.Get("Authorization") != "Bearer tok" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="test",scope="repository:org/app:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+				t.Errorf("Accept = %q", r.Header.Get("Accept"))
+			}
+			if strings.HasSuffix(r.URL.Path, "v2.0") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	c := &Client{Credentials: staticCreds{host: {Username: "bob", Password: "secret"}}}
+	ok, err := c.Check(context.Background(), host+"/org/app:v1.0")
+	if err != nil || !ok {
+		t.Fatalf("Check v1.0 = %v, %v; want true", ok, err)
+	}
+	ok, err = c.Check(context.Background(), host+"/org/app:v2.0")
+	if err != nil || ok {
+		t.Fatalf("Check v2.0 = %v, %v; want false", ok, err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want 1 (cached)", tokenRequests)
+	}
+}
+
+func TestCheck_BasicChallenge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "bob" || pass != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	ok, err := (&Client{}).Check(context.Background(), host+"/app:1.0")
+	if err != nil || ok {
+		t.Fatalf("anonymous Check = %v, %v; want false", ok, err)
+	}
+	c := &Client{Credentials: staticCreds{host: {Username: "bob", Password: "secret"}}}
+	ok, err = c.Check(context.Background(), host+"/app:1.0")
+	if err != nil || !ok {
+		t.Fatalf("Check = %v, %v; want true", ok, err)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:a/b:pull,push"`)
+	if scheme != "bearer" {
+		t.Errorf("scheme = %q", scheme)
+	}
+	want := map[string]string{"realm": "https://auth.docker.io/token", "service": "registry.docker.io", "scope": "repository:a/b:pull,push"}
+	for k, v := range want {
+		if params[k] != v {
+			t.Errorf("params[%s] = %q, want %q", k, params[k], v)
+		}
+	}
+}
+
+func TestDockerConfigGet(t *testing.T) {
+	dir := t.TempDir()
+	auth := base64.StdEncoding.EncodeToString([]byte("bob:secret"))
+	cfg := `{"auths":{"ghcr.io":{"auth":"` + auth + `"},"https://index.docker.io/v1/":{"auth":"` + auth + `"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dir)
+	dc, err := LoadDockerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, reg := range []string{"ghcr.io", DockerHub} {
+		creds, ok, err := dc.Get(context.Background(), reg)
+		if err != nil || !ok || creds.Username != "bob" || creds.Password != "secret" {
+			t.Errorf("Get(%s) = %+v, %v, %v", reg, creds, ok, err)
+		}
+	}
+	if _, ok, _ := dc.Get(context.Background(), "quay.io"); ok {
+		t.Errorf("Get(quay.io): expected no credentials")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	index := `{"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[
+		{"digest":"sha256:amd","platform":{"os":"linux","architecture":"amd64"}},
+		{"digest":"sha256:arm","platform":{"os":"linux","architecture":"arm64","variant":"v8"}},
+		{"digest":"sha256:att","platform":{"os":"unknown","architecture":"unknown"}}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/app/manifests/v1.2.3":
+			_, _ = w.Write([]byte(index))
+		case "/v2/app/manifests/sha256:amd":
+			_, _ = w.Write([]byte(`{"config":{"digest":"sha256:cfgamd"}}`))
+		case "/v2/app/manifests/sha256:arm":
+			_, _ = w.Write([]byte(`{"config":{"digest":"sha256:cfgarm"}}`))
+		case "/v2/app/blobs/sha256:cfgamd":
+			_, _ = w.Write([]byte(`{"os":"linux","architecture":"amd64","config":{"Labels":{"org.opencontainers.image.version":"1.2.3"}}}`))
+		case "/v2/app/blobs/sha256:cfgarm":
+			_, _ = w.Write([]byte(`{"os":"linux","architecture":"arm64","config":{"Labels":{"org.opencontainers.image.version":"1.2.2"}}}`))
+		case "/v2/single/manifests/v1.2.3":
+			_, _ = w.Write([]byte(`{"config":{"digest":"sha256:cfgamd"}}`))
+		case "/v2/single/blobs/sha256:cfgamd":
+			_, _ = w.Write([]byte(`{"os":"linux","architecture":"amd64","config":{"Labels":{}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	c := &Client{}
+	ctx := context.Background()
+	label := "org.opencontainers.image.version"
+	tests := []struct {
+		image   string
+		opts    VerifyOptions
+		wantErr string
+	}{
+		{"app:v1.2.3", VerifyOptions{Platforms: []string{"linux/amd64", "linux/arm64"}}, ""},
+		{"app:v1.2.3", VerifyOptions{Platforms: []string{"linux/arm64/v8"}}, ""},
+		{"app:v1.2.3", VerifyOptions{Platforms: []string{"linux/amd64", "linux/s390x"}}, "missing platform(s) linux/s390x (has linux/amd64, linux/arm64/v8)"},
+		{"app:v1.2.3", VerifyOptions{Platforms: []string{"linux/amd64"}, Labels: map[string]string{label: "v1.2.3"}}, ""},
+		{"app:v1.2.3", VerifyOptions{Labels: map[string]string{label: "v1.2.3"}}, `(linux/arm64/v8): label org.opencontainers.image.version = "1.2.2", want "v1.2.3"`},
+		{"single:v1.2.3", VerifyOptions{Platforms: []string{"linux/amd64"}}, ""},
+		{"single:v1.2.3", VerifyOptions{Platforms: []string{"linux/arm64"}}, "missing platform(s) linux/arm64 (has linux/amd64)"},
+		{"single:v1.2.3", VerifyOptions{Labels: map[string]string{label: "1.2.3"}}, "label org.opencontainers.image.version not set"},
+	}
+	for _, tt := range tests {
+		err := c.Verify(ctx, host+"/"+tt.image, tt.opts)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Verify(%s, %+v): %v", tt.image, tt.opts, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Verify(%s, %+v) = %v, want error containing %q", tt.image, tt.opts, err, tt.wantErr)
+		}
+	}
+}
@@ -7,13 +7,39 @@ import (
 	"strings"
 )
 
-// Version is a semantic version (major.minor.patch with optional prerelease rcN or aN).
+// Scheme selects the tag format used for parsing, ordering, and generating tags.
+type Scheme string
+
+const (
+	// SchemePEP440 accepts X.Y.Z, X.Y.ZrcN and X.Y.ZaN (the default).
+	SchemePEP440 Scheme = "pep440"
+	// SchemeSemVer2 accepts SemVer 2.0 tags: X.Y.Z[-prerelease][+build] (e.g. v1.4.0-rc.1+build.7).
+	SchemeSemVer2 Scheme = "semver2"
+)
+
+// ParseScheme returns the scheme for a config value. Empty means SchemePEP440.
+func ParseScheme(s string) (Scheme, error) {
+	switch Scheme(strings.ToLower(strings.TrimSpace(s))) {
+	case "", SchemePEP440:
+		return SchemePEP440, nil
+	case SchemeSemVer2:
+		return SchemeSemVer2, nil
+	}
+	return "", fmt.Errorf("unknown tag scheme %q (want pep440 or semver2)", s)
+}
+
+// Version is a semantic version (major.minor.patch with optional prerelease rcN or aN,
+// or a SemVer 2.0 prerelease and build metadata).
 type Version struct {
 	Major   int
 	Minor   int
 	Patch   int
-	PreKind string // "rc", "alpha" (or "a"), or ""
-	PreNum  int    // e.g. 0 in rc0, 1 in a1
+	PreKind string // "rc", "alpha" (or "a"), or ""; for SemVer 2.0 the first prerelease identifier
+	PreNum  int    // e.g. 0 in rc0, 1 in a1, 2 in beta.2
+	// Prerelease is the full SemVer 2.0 prerelease (e.g. "beta.2"); empty for PEP 440 versions.
+	Prerelease string
+	// Build is SemVer 2.0 build metadata (e.g. "build.7"). It is ignored when ordering versions.
+	Build string
 }
 
 // Tag formats we accept: v?X.Y.Z, v?X.Y.ZrcN, v?X.Y.ZaN (X.Y.Z = digits).
@@ -23,9 +49,22 @@ var (
 	alphaRegex  = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)a(\d+)$`)
 )
 
-// ParseTag parses a tag string into a Version. Returns nil if the tag doesn't match.
+// semver2Regex is the SemVer 2.0 grammar (https://semver.org) with an optional leading 'v'.
+var semver2Regex = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// ParseTag parses a PEP 440-style tag string into a Version. Returns nil if the tag doesn't match.
 func ParseTag(tag string) *Version {
+	return ParseTagScheme(tag, SchemePEP440)
+}
+
+// ParseTagScheme parses a tag string using the given scheme. Returns nil if the tag doesn't match.
+func ParseTagScheme(tag string, scheme Scheme) *Version {
 	tag = strings.TrimSpace(tag)
+	if scheme == SchemeSemVer2 {
+		return parseSemVer2(tag)
+	}
 	if m := rcRegex.FindStringSubmatch(tag); len(m) == 5 {
 		maj, _ := strconv.Atoi(m[1])
 		min, _ := strconv.Atoi(m[2])
@@ -49,8 +88,35 @@ func ParseTag(tag string) *Version {
 	return nil
 }
 
+// parseSemVer2 parses X.Y.Z[-pre][+build]. PreKind is the first prerelease identifier and PreNum
+// the last one when it is numeric (beta.2 → beta, 2; alpha → alpha, 0).
+func parseSemVer2(tag string) *Version {
+	m := semver2Regex.FindStringSubmatch(tag)
+	if len(m) != 6 {
+		return nil
+	}
+	maj, err1 := strconv.Atoi(m[1])
+	min, err2 := strconv.Atoi(m[2])
+	patch, err3 := strconv.Atoi(m[3])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil
+	}
+	v := &Version{Major: maj, Minor: min, Patch: patch, Prerelease: m[4], Build: m[5]}
+	if v.Prerelease != "" {
+		ids := strings.Split(v.Prerelease, ".")
+		v.PreKind = ids[0]
+		if len(ids) > 1 {
+			if n, err := strconv.Atoi(ids[len(ids)-1]); err == nil {
+				v.PreNum = n
+			}
+		}
+	}
+	return v
+}
+
 // Less returns true if v is less than o (v comes before o in release order).
 // Stable X.Y.Z is greater than X.Y.ZrcN or X.Y.ZaN; rc0 < rc1 < stable.
+// SemVer 2.0 prereleases are ordered by identifier precedence; build metadata is ignored.
 func (v *Version) Less(o *Version) bool {
 	if v.Major != o.Major {
 		return v.Major < o.Major
@@ -61,6 +127,9 @@ func (v *Version) Less(o *Version) bool {
 	if v.Patch != o.Patch {
 		return v.Patch < o.Patch
 	}
+	if v.Prerelease != "" || o.Prerelease != "" {
+		return comparePrerelease(v.Prerelease, o.Prerelease) < 0
+	}
 	// Same base: stable > rc > alpha; then by pre number
 	vStable := v.PreKind == ""
 	oStable := o.PreKind == ""
@@ -74,14 +143,84 @@ func (v *Version) Less(o *Version) bool {
 	return v.PreNum < o.PreNum
 }
 
+// comparePrerelease compares SemVer 2.0 prerelease strings (-1, 0, 1). An empty prerelease (stable)
+// is greater than any prerelease. Identifiers are compared left to right: numeric ones numerically,
+// alphanumeric ones in ASCII order, numeric < alphanumeric; a longer list wins when all earlier identifiers are equal.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func compareIdentifier(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
+	switch {
+	case aNum && bNum:
+		// No leading zeros in numeric identifiers, so longer means larger.
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// equal returns true if v and o have the same precedence.
+func (v *Version) equal(o *Version) bool {
+	return !v.Less(o) && !o.Less(v)
+}
+
 // String returns the version as a tag string (no leading 'v' for prerelease, optional for stable).
+// SemVer 2.0 versions include "-prerelease" and "+build" when set.
 func (v Version) String() string {
 	base := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
-	if v.PreKind == "rc" {
-		return base + fmt.Sprintf("rc%d", v.PreNum)
+	if v.Prerelease != "" {
+		base += "-" + v.Prerelease
+	} else if v.PreKind == "rc" {
+		base += fmt.Sprintf("rc%d", v.PreNum)
+	} else if v.PreKind == "a" {
+		base += fmt.Sprintf("a%d", v.PreNum)
 	}
-	if v.PreKind == "a" {
-		return base + fmt.Sprintf("a%d", v.PreNum)
+	if v.Build != "" {
+		base += "+" + v.Build
 	}
 	return base
 }
@@ -93,10 +232,10 @@ func (v Version) StringWithV() string {
 
 // IsStable returns true for X.Y.Z with no prerelease.
 func (v *Version) IsStable() bool {
-	return v != nil && v.PreKind == ""
+	return v != nil && v.PreKind == "" && v.Prerelease == ""
 }
 
-// Base returns the same version with prerelease stripped (e.g. 1.2.3rc2 → 1.2.3).
+// Base returns the same version with prerelease and build metadata stripped (e.g. 1.2.3rc2 → 1.2.3).
 func (v *Version) Base() Version {
 	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
 }
@@ -134,12 +273,31 @@ func (v *Version) NextAlpha(existingANum *int) Version {
 	return Version{Major: base.Major, Minor: base.Minor, Patch: base.Patch, PreKind: "a", PreNum: 0}
 }
 
+// NextSemVer2Pre returns the next SemVer 2.0 prerelease kind.N for this base: kind.(N+1) if
+// existingNum is set, otherwise kind.1 (e.g. 1.2.3-rc.1, 1.2.3-rc.2).
+func (v *Version) NextSemVer2Pre(kind string, existingNum *int) Version {
+	n := 1
+	if existingNum != nil {
+		n = *existingNum + 1
+	}
+	base := v.Base()
+	base.PreKind = kind
+	base.PreNum = n
+	base.Prerelease = fmt.Sprintf("%s.%d", kind, n)
+	return base
+}
+
 // LatestTag returns the latest semantic version tag from the list (by version order).
 // Only tags that parse as semver are considered. Returns empty string if none parse.
 func LatestTag(tags []string) string {
+	return LatestTagScheme(tags, SchemePEP440)
+}
+
+// LatestTagScheme is LatestTag for the given tag scheme.
+func LatestTagScheme(tags []string, scheme Scheme) string {
 	var max *Version
 	for _, tagStr := range tags {
-		p := ParseTag(tagStr)
+		p := ParseTagScheme(tagStr, scheme)
 		if p == nil {
 			continue
 		}
@@ -153,7 +311,7 @@ func LatestTag(tags []string) string {
 	}
 	// Prefer returning the original tag string if it had a 'v' prefix
 	for _, tagStr := range tags {
-		if p := ParseTag(tagStr); p != nil && p.Major == max.Major && p.Minor == max.Minor && p.Patch == max.Patch && p.PreKind == max.PreKind && p.PreNum == max.PreNum {
+		if p := ParseTagScheme(tagStr, scheme); p != nil && p.equal(max) {
 			return tagStr
 		}
 	}
@@ -166,9 +324,14 @@ func LatestTag(tags []string) string {
 // LatestStableTag returns the latest stable (non-alpha, non-rc) semver tag from the list.
 // Use this as the default "previous release" when building changelogs. Returns empty string if no stable tags exist.
 func LatestStableTag(tags []string) string {
+	return LatestStableTagScheme(tags, SchemePEP440)
+}
+
+// LatestStableTagScheme is LatestStableTag for the given tag scheme.
+func LatestStableTagScheme(tags []string, scheme Scheme) string {
 	var max *Version
 	for _, tagStr := range tags {
-		p := ParseTag(tagStr)
+		p := ParseTagScheme(tagStr, scheme)
 		if p == nil || !p.IsStable() {
 			continue
 		}
@@ -181,13 +344,40 @@ func LatestStableTag(tags []string) string {
 		return ""
 	}
 	for _, tagStr := range tags {
-		if p := ParseTag(tagStr); p != nil && p.IsStable() && p.Major == max.Major && p.Minor == max.Minor && p.Patch == max.Patch {
+		if p := ParseTagScheme(tagStr, scheme); p != nil && p.IsStable() && p.equal(max) {
 			return tagStr
 		}
 	}
 	return max.StringWithV()
 }
 
+// TrimTagPrefix returns the tags that start with prefix, with the prefix removed
+// (e.g. "api/v1.2.3" → "v1.2.3" for prefix "api/"). Returns tags unchanged when prefix is empty.
+func TrimTagPrefix(tags []string, prefix string) []string {
+	if prefix == "" {
+		return tags
+	}
+	var out []string
+	for _, t := range tags {
+		if strings.HasPrefix(t, prefix) {
+			out = append(out, strings.TrimPrefix(t, prefix))
+		}
+	}
+	return out
+}
+
+// AddTagPrefix prepends prefix to tag. The tag's leading 'v' is dropped when the prefix already
+// ends in 'v' (worker-v + v0.9.2 → worker-v0.9.2). Returns "" for an empty tag.
+func AddTagPrefix(prefix, tag string) string {
+	if tag == "" || prefix == "" {
+		return tag
+	}
+	if strings.HasSuffix(prefix, "v") {
+		tag = strings.TrimPrefix(tag, "v")
+	}
+	return prefix + tag
+}
+
 // NextFromTags computes the next version tag from a list of existing tags.
 // If rc is true, returns X.Y.ZrcN (next rc: either X.Y.Zrc0 for next release, or rc(N+1) if X.Y.Zrc* exist).
 // If alpha is true, returns X.Y.ZaN (next alpha, same logic).
@@ -195,88 +385,71 @@ func LatestStableTag(tags []string) string {
 // If release is true and major is true, returns the next major version v(X+1).0.0.
 // Otherwise returns the next patch version vX.Y.(Z+1).
 func NextFromTags(tags []string, rc, alpha, release, major bool) string {
+	return NextFromTagsScheme(tags, SchemePEP440, rc, alpha, release, major)
+}
+
+// NextFromTagsScheme is NextFromTags for the given tag scheme. With SchemeSemVer2, prereleases
+// are vX.Y.Z-rc.N and vX.Y.Z-alpha.N (starting at 1) and build metadata on existing tags is ignored.
+func NextFromTagsScheme(tags []string, scheme Scheme, rc, alpha, release, major bool) string {
+	rcKind, alphaKind := "rc", "a"
+	if scheme == SchemeSemVer2 {
+		alphaKind = "alpha"
+	}
 	var maxStable *Version
-	rcBases := make(map[string]int) // "X.Y.Z" -> max rc N
-	alphaBases := make(map[string]int)
+	rcBases := make(map[Version]int) // X.Y.Z -> max rc N
+	alphaBases := make(map[Version]int)
 
 	for _, tagStr := range tags {
-		p := ParseTag(tagStr)
+		p := ParseTagScheme(tagStr, scheme)
 		if p == nil {
 			continue
 		}
 		v := *p
 		if v.IsStable() {
 			if maxStable == nil || maxStable.Less(&v) {
-				c := v
+				c := v.Base()
 				maxStable = &c
 			}
 		}
-		if v.PreKind == "rc" {
-			key := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
-			if cur, ok := rcBases[key]; !ok || v.PreNum > cur {
-				rcBases[key] = v.PreNum
+		if v.PreKind == rcKind {
+			if cur, ok := rcBases[v.Base()]; !ok || v.PreNum > cur {
+				rcBases[v.Base()] = v.PreNum
 			}
 		}
-		if v.PreKind == "a" {
-			key := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
-			if cur, ok := alphaBases[key]; !ok || v.PreNum > cur {
-				alphaBases[key] = v.PreNum
+		if v.PreKind == alphaKind {
+			if cur, ok := alphaBases[v.Base()]; !ok || v.PreNum > cur {
+				alphaBases[v.Base()] = v.PreNum
 			}
 		}
 	}
 
-	if rc {
-		// Base = max of (next patch after max stable, each rc base from tags)
-		candidates := []Version{}
-		if maxStable != nil {
-			candidates = append(candidates, maxStable.NextPatch())
-		} else {
-			candidates = append(candidates, Version{Major: 1, Minor: 0, Patch: 0})
-		}
-		for k := range rcBases {
-			var parsed Version
-			if _, err := fmt.Sscanf(k, "%d.%d.%d", &parsed.Major, &parsed.Minor, &parsed.Patch); err != nil {
-				continue
-			}
-			candidates = append(candidates, parsed)
-		}
-		base := candidates[0]
-		for i := 1; i < len(candidates); i++ {
-			if base.Less(&candidates[i]) {
-				base = candidates[i]
-			}
-		}
-		key := fmt.Sprintf("%d.%d.%d", base.Major, base.Minor, base.Patch)
-		if n, has := rcBases[key]; has {
-			return base.NextRC(&n).String()
+	if rc || alpha {
+		bases, kind := rcBases, rcKind
+		if alpha {
+			bases, kind = alphaBases, alphaKind
 		}
-		return base.NextRC(nil).String()
-	}
-	if alpha {
-		candidates := []Version{}
+		// Base = max of (next patch after max stable, each prerelease base from tags)
+		base := Version{Major: 1, Minor: 0, Patch: 0}
 		if maxStable != nil {
-			candidates = append(candidates, maxStable.NextPatch())
-		} else {
-			candidates = append(candidates, Version{Major: 1, Minor: 0, Patch: 0})
+			base = maxStable.NextPatch()
 		}
-		for k := range alphaBases {
-			var parsed Version
-			if _, err := fmt.Sscanf(k, "%d.%d.%d", &parsed.Major, &parsed.Minor, &parsed.Patch); err != nil {
-				continue
+		for b := range bases {
+			if base.Less(&b) {
+				base = b
 			}
-			candidates = append(candidates, parsed)
 		}
-		base := candidates[0]
-		for i := 1; i < len(candidates); i++ {
-			if base.Less(&candidates[i]) {
-				base = candidates[i]
-			}
+		var existing *int
+		if n, has := bases[base]; has {
+			existing = &n
 		}
-		key := fmt.Sprintf("%d.%d.%d", base.Major, base.Minor, base.Patch)
-		if n, has := alphaBases[key]; has {
-			return base.NextAlpha(&n).String()
+		switch {
+		case scheme == SchemeSemVer2:
+			return base.NextSemVer2Pre(kind, existing).StringWithV()
+		case rc:
+			return base.NextRC(existing).String()
+		default:
+			return base.NextAlpha(existing).String()
 		}
-		return base.NextAlpha(nil).String()
 	}
 	if maxStable == nil {
 		return "v1.0.0"
@@ -131,3 +131,127 @@ func TestNextFromTags_ReleaseMajor(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTagScheme_SemVer2(t *testing.T) {
+	tests := []struct {
+		tag        string
+		wantNil    bool
+		major      int
+		minor      int
+		patch      int
+		prerelease string
+		build      string
+		preKind    string
+		preNum     int
+	}{
+		{"v1.4.0", false, 1, 4, 0, "", "", "", 0},
+		{"v1.4.0-beta.2", false, 1, 4, 0, "beta.2", "", "beta", 2},
+		{"v1.4.0-rc.1+build.7", false, 1, 4, 0, "rc.1", "build.7", "rc", 1},
+		{"2.0.0-alpha", false, 2, 0, 0, "alpha", "", "alpha", 0},
+		{"1.0.0+20130313144700", false, 1, 0, 0, "", "20130313144700", "", 0},
+		{"1.0.0-x.7.z.92", false, 1, 0, 0, "x.7.z.92", "", "x", 92},
+		{"1.2.3rc0", true, 0, 0, 0, "", "", "", 0},
+		{"01.2.3", true, 0, 0, 0, "", "", "", 0},
+		{"1.2.3-01", true, 0, 0, 0, "", "", "", 0},
+		{"1.2.3-", true, 0, 0, 0, "", "", "", 0},
+	}
+	for _, tt := range tests {
+		v := ParseTagScheme(tt.tag, SchemeSemVer2)
+		if tt.wantNil {
+			if v != nil {
+				t.Errorf("ParseTagScheme(%q, semver2) should return nil, got %+v", tt.tag, v)
+			}
+			continue
+		}
+		if v == nil {
+			t.Errorf("ParseTagScheme(%q, semver2) = nil", tt.tag)
+			continue
+		}
+		if v.Major != tt.major || v.Minor != tt.minor || v.Patch != tt.patch || v.Prerelease != tt.prerelease || v.Build != tt.build || v.PreKind != tt.preKind || v.PreNum != tt.preNum {
+			t.Errorf("ParseTagScheme(%q, semver2) = %+v", tt.tag, v)
+		}
+	}
+}
+
+func TestLess_SemVer2Precedence(t *testing.T) {
+	// Example from the SemVer 2.0 spec, in ascending order.
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1-rc.1", "1.1.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a := ParseTagScheme(ordered[i], SchemeSemVer2)
+		b := ParseTagScheme(ordered[i+1], SchemeSemVer2)
+		if a == nil || b == nil {
+			t.Fatalf("failed to parse %q or %q", ordered[i], ordered[i+1])
+		}
+		if !a.Less(b) || b.Less(a) {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+	a := ParseTagScheme("1.0.0-rc.1+build.1", SchemeSemVer2)
+	b := ParseTagScheme("1.0.0-rc.1+build.2", SchemeSemVer2)
+	if a.Less(b) || b.Less(a) {
+		t.Errorf("build metadata should not affect precedence")
+	}
+}
+
+func TestLatestTagScheme_SemVer2(t *testing.T) {
+	tags := []string{"v1.3.0", "v1.4.0-beta.2", "v1.4.0-beta.11", "v1.4.0-rc.1+build.7", "1.2.3rc0"}
+	if got := LatestTagScheme(tags, SchemeSemVer2); got != "v1.4.0-rc.1+build.7" {
+		t.Errorf("LatestTagScheme = %q, want v1.4.0-rc.1+build.7", got)
+	}
+	if got := LatestStableTagScheme(tags, SchemeSemVer2); got != "v1.3.0" {
+		t.Errorf("LatestStableTagScheme = %q, want v1.3.0", got)
+	}
+}
+
+func TestNextFromTagsScheme_SemVer2(t *testing.T) {
+	tests := []struct {
+		tags    []string
+		rc      bool
+		alpha   bool
+		release bool
+		want    string
+	}{
+		{nil, false, false, false, "v1.0.0"},
+		{nil, true, false, false, "v1.0.0-rc.1"},
+		{[]string{"v1.2.3"}, false, false, false, "v1.2.4"},
+		{[]string{"v1.2.3+build.5"}, false, false, false, "v1.2.4"},
+		{[]string{"v1.2.3"}, true, false, false, "v1.2.4-rc.1"},
+		{[]string{"v1.2.3", "v1.2.4-rc.1", "v1.2.4-rc.2+build.9"}, true, false, false, "v1.2.4-rc.3"},
+		{[]string{"v1.2.3", "v1.2.4-rc.1"}, false, true, false, "v1.2.4-alpha.1"},
+		{[]string{"v1.2.3", "v1.2.4-alpha"}, false, true, false, "v1.2.4-alpha.1"},
+		{[]string{"v1.2.3", "v1.2.4-beta.2"}, false, false, true, "v1.3.0"},
+	}
+	for _, tt := range tests {
+		got := NextFromTagsScheme(tt.tags, SchemeSemVer2, tt.rc, tt.alpha, tt.release, false)
+		if got != tt.want {
+			t.Errorf("NextFromTagsScheme(%v, semver2, rc=%v, alpha=%v, release=%v) = %q, want %q", tt.tags, tt.rc, tt.alpha, tt.release, got, tt.want)
+		}
+	}
+}
+
+func TestTagPrefix(t *testing.T) {
+	tags := []string{"v1.0.0", "api/v1.2.3", "api/v1.3.0rc0", "worker-v0.9.1", "worker-v0.9.0"}
+	api := TrimTagPrefix(tags, "api/")
+	if got := LatestStableTag(api); got != "v1.2.3" {
+		t.Errorf("LatestStableTag(api) = %q, want v1.2.3", got)
+	}
+	if got := AddTagPrefix("api/", NextFromTags(api, false, false, false, false)); got != "api/v1.2.4" {
+		t.Errorf("next api tag = %q, want api/v1.2.4", got)
+	}
+	if got := AddTagPrefix("api/", NextFromTags(api, true, false, false, false)); got != "api/1.3.0rc1" {
+		t.Errorf("next api rc = %q, want api/1.3.0rc1", got)
+	}
+	worker := TrimTagPrefix(tags, "worker-v")
+	if got := AddTagPrefix("worker-v", LatestStableTag(worker)); got != "worker-v0.9.1" {
+		t.Errorf("latest worker tag = %q, want worker-v0.9.1", got)
+	}
+	if got := AddTagPrefix("worker-v", NextFromTags(worker, false, false, false, false)); got != "worker-v0.9.2" {
+		t.Errorf("next worker tag = %q, want worker-v0.9.2", got)
+	}
+	if got := AddTagPrefix("", "v1.0.0"); got != "v1.0.0" {
+		t.Errorf("AddTagPrefix without prefix = %q", got)
+	}
+}
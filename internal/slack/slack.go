@@ -30,6 +30,18 @@ func NotifyRunComplete(webhookURL string, success bool, detail string) error {
 			text += " " + detail
 		}
 	}
+	return Notify(webhookURL, text)
+}
+
+// Notify posts text to the Slack Incoming Webhook at webhookURL.
+// If webhookURL is empty, SLACK_WEBHOOK_URL env is used. No-op if both are empty.
+func Notify(webhookURL, text string) error {
+	if webhookURL == "" {
+		webhookURL = os.Getenv("SLACK_WEBHOOK_URL")
+	}
+	if webhookURL == "" {
+		return nil
+	}
 	body, err := json.Marshal(map[string]string{"text": text})
 	if err != nil {
 		return fmt.Errorf("slack marshal: %w", err)
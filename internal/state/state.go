@@ -0,0 +1,148 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultFile is the default release state file path under the repo root.
+const DefaultFile = ".releasebot/release-state.json"
+
+// Step statuses recorded in the state file.
+const (
+	StepDone    = "done"
+	StepSkipped = "skipped"
+	StepFailed  = "error"
+)
+
+// StepRecord is the recorded outcome of a single release step.
+type StepRecord struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`   // release step type (e.g. commit_tag, push)
+	Status     string    `json:"status"` // "done" | "skipped" | "error"
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Release is the persisted state of an in-progress release, so that `release --resume` can pick up
+// from the first incomplete step using the recorded tag instead of computing a new one.
+type Release struct {
+	Tag       string `json:"tag"`
+	PrevTag   string `json:"prev_tag"`
+	Branch    string `json:"branch"`
+	Remote    string `json:"remote"`
+	Component string `json:"component,omitempty"`  // monorepo component (--component), if any
+	BaseSHA   string `json:"base_sha,omitempty"`   // HEAD before the release started (rollback target)
+	CommitSHA string `json:"commit_sha,omitempty"` // release commit created by the "Commit & tag" step
+	// GitHubReleaseID is the GitHub Release created by the github_release step (0 if it updated an existing one).
+	GitHubReleaseID int64 `json:"github_release_id,omitempty"`
+	// GitLabReleaseTag is the tag of the GitLab Release created by the github_release step ("" if it updated an
+	// existing one or the project is on GitHub).
+	GitLabReleaseTag string `json:"gitlab_release_tag,omitempty"`
+	// Changelog is the changelog path relative to the repo root. PrevChangelog holds its contents
+	// before the release wrote to it (ChangelogExisted is false if the file did not exist) so rollback can restore it.
+	Changelog        string       `json:"changelog,omitempty"`
+	PrevChangelog    string       `json:"prev_changelog,omitempty"`
+	ChangelogExisted bool         `json:"changelog_existed,omitempty"`
+	Steps            []StepRecord `json:"steps"`
+	StartedAt        time.Time    `json:"started_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+// New returns a fresh release state for tag.
+func New(tag, prevTag, branch, remote string) *Release {
+	now := time.Now().UTC()
+	return &Release{
+		Tag:       tag,
+		PrevTag:   prevTag,
+		Branch:    branch,
+		Remote:    remote,
+		StartedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Load reads release state from path. Returns (nil, nil) if the file does not exist.
+func Load(path string) (*Release, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read release state: %w", err)
+	}
+	var r Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse release state: %w", err)
+	}
+	return &r, nil
+}
+
+// Save writes the state to path, creating the parent directory if needed.
+func (r *Release) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	r.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal release state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write release state: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the state file at path. A missing file is not an error.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove release state: %w", err)
+	}
+	return nil
+}
+
+// Step returns the record for the named step, or nil if the step has not run.
+func (r *Release) Step(name string) *StepRecord {
+	for i := range r.Steps {
+		if r.Steps[i].Name == name {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+// RanType returns true if any step of the given type was started, i.e. it completed or failed part-way (rollback candidates).
+func (r *Release) RanType(typ string) bool {
+	for _, s := range r.Steps {
+		if s.Type == typ && (s.Status == StepDone || s.Status == StepFailed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Completed returns true if the named step finished successfully (done or skipped).
+func (r *Release) Completed(name string) bool {
+	s := r.Step(name)
+	return s != nil && (s.Status == StepDone || s.Status == StepSkipped)
+}
+
+// Record sets the outcome of the named step of type typ, replacing any earlier record for it.
+func (r *Release) Record(name, typ string, skipped bool, err error) {
+	rec := StepRecord{Name: name, Type: typ, Status: StepDone, FinishedAt: time.Now().UTC()}
+	if err != nil {
+		rec.Status = StepFailed
+		rec.Error = err.Error()
+	} else if skipped {
+		rec.Status = StepSkipped
+	}
+	if s := r.Step(name); s != nil {
+		*s = rec
+		return
+	}
+	r.Steps = append(r.Steps, rec)
+}
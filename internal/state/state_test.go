@@ -0,0 +1,90 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecord(t *testing.T) {
+	r := New("v1.2.3", "v1.2.2", "main", "origin")
+	r.Record("Changelog", "changelog", false, nil)
+	r.Record("Just", "just", true, nil)
+	r.Record("Push", "push", false, errors.New("rejected"))
+	r.Record("Notify", "notify", true, nil)
+
+	tests := []struct {
+		name      string
+		typ       string
+		completed bool
+		ranType   bool
+	}{
+		{"Changelog", "changelog", true, true},
+		{"Just", "just", true, false},
+		{"Push", "push", false, true},
+		{"Commit & tag", "commit_tag", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Completed(tt.name); got != tt.completed {
+				t.Errorf("Completed(%q) = %v, want %v", tt.name, got, tt.completed)
+			}
+			if got := r.RanType(tt.typ); got != tt.ranType {
+				t.Errorf("RanType(%q) = %v, want %v", tt.typ, got, tt.ranType)
+			}
+		})
+	}
+	if s := r.Step("Push"); s == nil || s.Status != StepFailed || s.Error != "rejected" {
+		t.Errorf("Step(Push) = %+v, want failed with error", s)
+	}
+
+	// Recording a step again replaces its earlier outcome.
+	r.Record("Push", "push", false, nil)
+	if !r.Completed("Push") {
+		t.Error("Push not completed after re-recording it as done")
+	}
+	if len(r.Steps) != 4 {
+		t.Errorf("len(Steps) = %d, want 4", len(r.Steps))
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".releasebot", "release-state.json")
+	r := New("api/v1.2.3", "api/v1.2.2", "main", "origin")
+	r.Component = "api"
+	r.CommitSHA = "abc123"
+	r.GitHubReleaseID = 42
+	r.Changelog = "CHANGELOG.md"
+	r.PrevChangelog = "## v1.2.2\n"
+	r.ChangelogExisted = true
+	r.Record("Commit & tag", "commit_tag", false, nil)
+	if err := r.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Tag != r.Tag || got.PrevTag != r.PrevTag || got.Component != "api" || got.CommitSHA != "abc123" ||
+		got.GitHubReleaseID != 42 || got.PrevChangelog != r.PrevChangelog || !got.ChangelogExisted {
+		t.Errorf("Load = %+v, want %+v", got, r)
+	}
+	if !got.Completed("Commit & tag") || !got.RanType("commit_tag") {
+		t.Errorf("loaded steps = %+v, want Commit & tag done", got.Steps)
+	}
+
+	if err := Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(path); err != nil {
+		t.Errorf("Remove of missing file: %v", err)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	r, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if r != nil || err != nil {
+		t.Errorf("Load(missing) = %v, %v, want nil, nil", r, err)
+	}
+}
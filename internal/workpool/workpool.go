@@ -0,0 +1,110 @@
+// Package workpool runs indexed jobs on a bounded number of goroutines, with an optional requests-per-minute limiter.
+package workpool
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ForEach calls fn(ctx, i) for every i in [0, n) using at most workers goroutines (workers < 1 means 1).
+// Results should be written by index so callers keep input order. onDone, if non-nil, is called after each
+// job finishes with the number of finished jobs so far; calls are serialized and completed increases by one each time.
+// The first error cancels the context passed to the remaining jobs and is returned; jobs not yet started are skipped.
+func ForEach(ctx context.Context, n, workers int, fn func(ctx context.Context, i int) error, onDone func(completed, total int)) error {
+	if n <= 0 {
+		return nil
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	var (
+		mu        sync.Mutex
+		completed int
+		firstErr  error
+		wg        sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				err := fn(ctx, i)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				completed++
+				if onDone != nil {
+					onDone(completed, n)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// Limiter spaces calls evenly so that at most rpm start per minute. A nil Limiter never waits.
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter returns a limiter allowing rpm calls per minute, or nil (unlimited) when rpm <= 0.
+func NewLimiter(rpm int) *Limiter {
+	if rpm <= 0 {
+		return nil
+	}
+	return &Limiter{interval: time.Minute / time.Duration(rpm)}
+}
+
+// Wait blocks until the next call is allowed or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
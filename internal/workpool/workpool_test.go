@@ -0,0 +1,96 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach_OrderAndConcurrency(t *testing.T) {
+	const n = 50
+	results := make([]int, n)
+	var running, maxRunning int32
+	var progress []int
+	err := ForEach(context.Background(), n, 4, func(ctx context.Context, i int) error {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if cur <= m || atomic.CompareAndSwapInt32(&maxRunning, m, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond * time.Duration(n-i) / 10)
+		results[i] = i * i
+		atomic.AddInt32(&running, -1)
+		return nil
+	}, func(completed, total int) {
+		progress = append(progress, completed)
+		if total != n {
+			t.Errorf("total = %d, want %d", total, n)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range results {
+		if r != i*i {
+			t.Fatalf("results[%d] = %d", i, r)
+		}
+	}
+	if maxRunning > 4 {
+		t.Errorf("max concurrent = %d, want <= 4", maxRunning)
+	}
+	if len(progress) != n {
+		t.Fatalf("onDone called %d times, want %d", len(progress), n)
+	}
+	for i, c := range progress {
+		if c != i+1 {
+			t.Fatalf("progress[%d] = %d, want %d", i, c, i+1)
+		}
+	}
+}
+
+func TestForEach_Error(t *testing.T) {
+	boom := errors.New("boom")
+	var started int32
+	err := ForEach(context.Background(), 100, 2, func(ctx context.Context, i int) error {
+		atomic.AddInt32(&started, 1)
+		if i == 3 {
+			return boom
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Millisecond):
+		}
+		return nil
+	}, nil)
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if started == 100 {
+		t.Errorf("expected remaining jobs to be skipped after the error")
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	if err := NewLimiter(0).Wait(context.Background()); err != nil {
+		t.Fatalf("unlimited Wait: %v", err)
+	}
+	l := NewLimiter(600) // one call every 100ms
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("3 calls at 600 rpm took %v, want >= 200ms spacing", elapsed)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Errorf("Wait with cancelled context should fail")
+	}
+}